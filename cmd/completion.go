@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/infracasts/terrawrap-cli/terraform"
+	"github.com/spf13/cobra"
+	licenses "github.com/spf13/cobra-cli/cmd"
+	"github.com/spf13/viper"
+)
+
+// typeNames lists the resource and data source types of a provider schema;
+// it's cached to disk, as loading the schema takes too long to do on every
+// tab press.
+type typeNames struct {
+	Resources   []string `json:"resources"`
+	DataSources []string `json:"data_sources"`
+}
+
+// completeResourceTypes offers the provider's resource types; data sources
+// are left out, as generate and inspect only wrap resources.
+func completeResourceTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names, err := cachedTypeNames()
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var completions []string
+	for _, name := range names.Resources {
+		if strings.HasPrefix(name, toComplete) {
+			completions = append(completions, name)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeLicenses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for key, l := range licenses.Licenses {
+		if strings.HasPrefix(key, toComplete) {
+			completions = append(completions, key+"\t"+l.Name)
+		}
+	}
+	sort.Strings(completions)
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProviderVersions offers the versions already in the doc cache.
+func completeProviderVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cached, err := terraform.ListDocCache(docCacheDir())
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	seen := make(map[string]bool)
+	var completions []string
+	for _, docs := range cached {
+		if docs.Complete && !seen[docs.Version] && strings.HasPrefix(docs.Version, toComplete) {
+			seen[docs.Version] = true
+			completions = append(completions, docs.Version+"\t"+docs.Provider)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// cachedTypeNames reads the type names of the schema source from the
+// completion cache, loading the schema and caching them if needed.
+func cachedTypeNames() (*typeNames, error) {
+	key, err := schemaSourceKey()
+	if err != nil {
+		return nil, err
+	}
+
+	cachePath := path.Join(cfgPath, "completion", key+".json")
+
+	names := &typeNames{}
+	if b, err := os.ReadFile(cachePath); err == nil && json.Unmarshal(b, names) == nil {
+		return names, nil
+	}
+
+	providerSchema, err := schemaSource().ProviderSchema(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range providerSchema.Resources {
+		names.Resources = append(names.Resources, name)
+	}
+	sort.Strings(names.Resources)
+
+	for name := range providerSchema.DataSources {
+		names.DataSources = append(names.DataSources, name)
+	}
+	sort.Strings(names.DataSources)
+
+	b, err := json.Marshal(names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode completion cache: %w", err)
+	}
+
+	// Completion still works without the cache, just slower
+	if err := os.MkdirAll(path.Dir(cachePath), 0754); err == nil {
+		_ = os.WriteFile(cachePath, b, 0644)
+	}
+
+	return names, nil
+}
+
+// schemaSourceKey identifies the schemas in use, by the terrawrap binary
+// (for the embedded provider) and the provider binary, if any, so the
+// completion cache is rebuilt whenever either changes.
+func schemaSourceKey() (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to find terrawrap executable: %w", err)
+	}
+
+	h := sha256.New()
+	for _, binary := range []string{executable, viper.GetString("provider-binary")} {
+		if binary == "" {
+			continue
+		}
+
+		info, err := os.Stat(binary)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat %s: %w", binary, err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\x00", binary, info.Size(), info.ModTime().UnixNano())
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil))[:16], nil
+}
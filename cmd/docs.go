@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/infracasts/terrawrap-cli/terraform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var docsKeep int
+
+func init() {
+	docsPruneCmd.Flags().IntVarP(&docsKeep, "keep", "k", 1, "number of most recently used versions to keep per provider")
+
+	docsCmd.AddCommand(docsListCmd)
+	docsCmd.AddCommand(docsFetchCmd)
+	docsCmd.AddCommand(docsPruneCmd)
+	docsCmd.AddCommand(docsRemoveCmd)
+	docsCmd.AddCommand(docsImportCmd)
+	rootCmd.AddCommand(docsCmd)
+}
+
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Manage the provider documentation cache",
+	Long: `Terrawrap keeps the provider documentation it parses under
+$HOME/.terrawrap/provider_docs/<provider>/<version>.`,
+}
+
+var docsListCmd = &cobra.Command{
+	Use:   "list",
+	Args:  cobra.NoArgs,
+	Short: "List the cached provider documentation",
+	Run: func(cmd *cobra.Command, args []string) {
+		cached, err := terraform.ListDocCache(docCacheDir())
+		cobra.CheckErr(err)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PROVIDER\tVERSION\tSIZE\tLAST USED\tSTATUS")
+		for _, docs := range cached {
+			status := "complete"
+			if !docs.Complete {
+				status = "incomplete"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", docs.Provider, docs.Version, formatSize(docs.Size),
+				docs.LastUsed.Local().Format(time.RFC3339), status)
+		}
+		cobra.CheckErr(w.Flush())
+	},
+}
+
+var docsFetchCmd = &cobra.Command{
+	Use:   "fetch [provider] [version]",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Download provider documentation ahead of time",
+	Long: `Terrawrap will populate the documentation cache for the provider
+(e.g. aws) and version (e.g. v4.29.0), unless it's already cached.
+
+The version defaults to --provider-version, or the provider's default.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		version := viper.GetString("provider-version")
+		if len(args) > 1 {
+			version = args[1]
+		}
+
+		tfProvider, err := fetchProviderDocs(args[0], version)
+		cobra.CheckErr(err)
+
+		fmt.Printf("Docs for %s %s are ready at\n%s\n", tfProvider.Name, tfProvider.Version, tfProvider.SourceRoot())
+	},
+}
+
+var docsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Args:  cobra.NoArgs,
+	Short: "Remove all but the most recently used versions of each provider's docs",
+	Run: func(cmd *cobra.Command, args []string) {
+		if docsKeep < 0 {
+			cobra.CheckErr(fmt.Errorf("--keep must not be negative"))
+		}
+
+		cached, err := terraform.ListDocCache(docCacheDir())
+		cobra.CheckErr(err)
+
+		// most recently used first, per provider
+		kept := make(map[string]int)
+		for _, docs := range cached {
+			if docs.Complete && kept[docs.Provider] < docsKeep {
+				kept[docs.Provider]++
+				continue
+			}
+
+			cobra.CheckErr(removeCachedDocs(docs))
+			fmt.Printf("Removed %s %s (%s)\n", docs.Provider, docs.Version, formatSize(docs.Size))
+		}
+	},
+}
+
+var docsRemoveCmd = &cobra.Command{
+	Use:   "remove [provider] [version]",
+	Args:  cobra.ExactArgs(2),
+	Short: "Remove a version of a provider's docs from the cache",
+	Run: func(cmd *cobra.Command, args []string) {
+		cached, err := terraform.ListDocCache(docCacheDir())
+		cobra.CheckErr(err)
+
+		version := terraform.NormalizeVersion(args[1])
+		for _, docs := range cached {
+			if docs.Provider == args[0] && docs.Version == version {
+				cobra.CheckErr(removeCachedDocs(docs))
+				fmt.Printf("Removed %s %s (%s)\n", docs.Provider, docs.Version, formatSize(docs.Size))
+				return
+			}
+		}
+
+		cobra.CheckErr(fmt.Errorf("no docs cached for %s %s", args[0], version))
+	},
+}
+
+var docsImportCmd = &cobra.Command{
+	Use:   "import [provider]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Seed the documentation cache from a local checkout or zip",
+	Long: `Terrawrap will populate the documentation cache for the provider
+(e.g. aws) from --docs-dir or --docs-archive, so no download is needed.
+
+The version cached is --provider-version, or the provider's default.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		tfProvider, err := terraform.GetProvider(args[0], viper.GetString("provider-version"))
+		cobra.CheckErr(err)
+
+		dir, archive := viper.GetString("docs-dir"), viper.GetString("docs-archive")
+		if dir == "" && archive == "" {
+			cobra.CheckErr(fmt.Errorf("one of --docs-dir or --docs-archive is required"))
+		}
+
+		providerDocPath := docCachePath(tfProvider)
+		tfProvider.SetRootDocPath(providerDocPath)
+		tfProvider.SetChecksum(viper.GetString("docs-sha256"))
+
+		unlock, err := tfProvider.LockDocCache(viper.GetDuration("docs-lock-timeout"))
+		cobra.CheckErr(err)
+		defer unlockDocCache(unlock)
+
+		// Replaces whatever was cached before
+		if dir != "" {
+			cobra.CheckErr(tfProvider.ImportDocsDir(dir))
+		} else {
+			cobra.CheckErr(tfProvider.ImportDocsArchive(archive))
+		}
+
+		fmt.Printf("Imported %s %s docs to\n%s\n", tfProvider.Name, tfProvider.Version, providerDocPath)
+	},
+}
+
+// removeCachedDocs deletes cached docs once no other process is populating
+// them.
+func removeCachedDocs(docs terraform.CachedDocs) error {
+	tfProvider := &terraform.Provider{Name: docs.Provider, Version: docs.Version}
+	tfProvider.SetRootDocPath(docs.Path)
+
+	unlock, err := tfProvider.LockDocCache(viper.GetDuration("docs-lock-timeout"))
+	if err != nil {
+		return err
+	}
+	defer unlockDocCache(unlock)
+
+	return tfProvider.RemoveDocCache()
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/infracasts/terrawrap-cli/terraform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [terraform_resource_type].[argument]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Describe an argument or attribute of a resource",
+	Long: `Terrawrap will describe an argument or attribute of a resource from its
+schema and documentation, e.g.
+
+  terrawrap explain aws_secretsmanager_secret.recovery_window_in_days
+  terrawrap explain aws_secretsmanager_secret.replica.region`,
+	ValidArgsFunction: completeExplainPath,
+	Run: func(cmd *cobra.Command, args []string) {
+		parts := strings.Split(args[0], ".")
+		if len(parts) < 2 || parts[1] == "" {
+			cobra.CheckErr(fmt.Errorf("expected <resource_type>.<argument>, e.g. aws_secretsmanager_secret.name"))
+		}
+		resourceType, fieldPath := parts[0], parts[1:]
+		// Nothing is generated, so the resource's local name doesn't matter
+		localName := "default"
+
+		tfProvider, err := fetchDocProvider(resourceType, viper.GetString("provider-version"))
+		cobra.CheckErr(err)
+
+		resource, err := parseResource(resourceType, localName, "", "", &terraform.Module{}, tfProvider)
+		cobra.CheckErr(err)
+
+		s, err := resource.NestedSchema(fieldPath...)
+		entry, documented := describedEntry(resource, fieldPath)
+		if err != nil && !documented {
+			cobra.CheckErr(err)
+		}
+
+		explain(os.Stdout, resourceType, fieldPath, s, entry)
+	},
+}
+
+// describedEntry finds the docs of a field, preferring its argument entry
+// over its attribute entry.
+func describedEntry(resource terraform.TFResource, fieldPath []string) (terraform.IOEntry, bool) {
+	if len(fieldPath) > 1 {
+		return resource.NestedEntry(fieldPath...)
+	}
+
+	for _, argument := range resource.Arguments() {
+		if argument.Name == fieldPath[0] {
+			return terraform.IOEntry(argument), true
+		}
+	}
+
+	for _, attribute := range resource.Attributes() {
+		if attribute.Name == fieldPath[0] {
+			return terraform.IOEntry(attribute), true
+		}
+	}
+
+	return terraform.IOEntry{}, false
+}
+
+func explain(w io.Writer, resourceType string, fieldPath []string, s *schema.Schema, entry terraform.IOEntry) {
+	valueType := terraform.IOEntry{Schema: s}.ValueType()
+	if s == nil {
+		valueType = "string (not in schema)"
+	}
+
+	fmt.Fprintf(w, "RESOURCE:    %s\n", resourceType)
+	fmt.Fprintf(w, "FIELD:       %s <%s>\n", strings.Join(fieldPath, "."), valueType)
+
+	if s != nil {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "KIND:        %s\n", fieldKind(s))
+		// Undocumented fields have no docs default, just the schema's
+		defaulted := entry
+		defaulted.Schema = s
+		if def := defaulted.DefaultValue(); def != "" {
+			fmt.Fprintf(w, "DEFAULT:     %s (from the %s)\n", def, defaulted.DefaultSource())
+		}
+		fmt.Fprintf(w, "FORCE NEW:   %s\n", yesNo(s.ForceNew))
+		if len(s.ConflictsWith) > 0 {
+			fmt.Fprintf(w, "CONFLICTS:   %s\n", strings.Join(s.ConflictsWith, ", "))
+		}
+		fmt.Fprintf(w, "SENSITIVE:   %s\n", yesNo(s.Sensitive))
+		if s.Deprecated != "" {
+			fmt.Fprintf(w, "DEPRECATED:  %s\n", s.Deprecated)
+		} else {
+			fmt.Fprintf(w, "DEPRECATED:  %s\n", yesNo(entry.Deprecated))
+		}
+	}
+
+	description := entry.Description
+	if description == "" && s != nil {
+		description = s.Description
+	}
+	if description != "" {
+		fmt.Fprintf(w, "\nDESCRIPTION:\n%s\n", wrapText(description, "    ", 80))
+	}
+
+	if s == nil {
+		return
+	}
+
+	if nested, ok := s.Elem.(*schema.Resource); ok {
+		names := make([]string, 0, len(nested.Schema))
+		for name := range nested.Schema {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fmt.Fprintln(w, "\nFIELDS:")
+		for _, name := range names {
+			field := nested.Schema[name]
+			fmt.Fprintf(w, "    %s <%s> %s\n", name, terraform.IOEntry{Schema: field}.ValueType(), fieldKind(field))
+		}
+	}
+}
+
+func fieldKind(s *schema.Schema) string {
+	switch {
+	case s.Required:
+		return "argument (required)"
+	case s.Optional && s.Computed:
+		return "argument (optional, computed)"
+	case s.Optional:
+		return "argument (optional)"
+	default:
+		return "attribute"
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// wrapText breaks text into indented lines of at most width characters,
+// where words allow.
+func wrapText(text, indent string, width int) string {
+	var (
+		lines []string
+		line  string
+	)
+
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(indent)+len(line)+1+len(word) > width {
+			lines = append(lines, indent+line)
+			line = ""
+		}
+
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+
+	if line != "" {
+		lines = append(lines, indent+line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// completeExplainPath completes the resource type of an explain path; the
+// fields would need the resource's schema loaded, which is too slow.
+func completeExplainPath(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 || strings.Contains(toComplete, ".") {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions, directive := completeResourceTypes(cmd, args, toComplete)
+	for i := range completions {
+		completions[i] += "."
+	}
+
+	return completions, directive | cobra.ShellCompDirectiveNoSpace
+}
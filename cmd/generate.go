@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/infracasts/terrawrap-cli/terraform"
 	"github.com/spf13/cobra"
@@ -36,7 +37,9 @@ import (
 
 var (
 	varPrefix, attrPrefix, outputPath, author, license, resourceName string
-	standAlone, disableVarPrefix, disableAttrPrefix                  bool
+	variableOrder                                                    string
+	standAlone, disableVarPrefix, disableAttrPrefix, importBlock     bool
+	disableExamples, strict, echoOutputs, groupVariables             bool
 )
 
 func init() {
@@ -46,10 +49,16 @@ func init() {
 	generateCmd.Flags().StringVarP(&resourceName, "resource-name", "n", "default", `local name of the resource generated (e.g. "default" in `+"`"+`resource "aws_secretsmanager_secret" "default"`+"`"+`)`)
 
 	generateCmd.Flags().BoolVarP(&standAlone, "stand-alone", "s", false, "modules should be created in their own directory named for the resource type (e.g. '$OUTPUT_PATH/aws_secretsmanager_secret/*.tf')")
+	generateCmd.Flags().BoolVarP(&importBlock, "import-block", "", false, "write examples/import, a root configuration importing an existing resource into the module by an ID variable, and a README.md explaining its format")
 
 	// Variable/Output prefix disable
 	generateCmd.Flags().BoolVarP(&disableVarPrefix, "no-var-prefix", "", false, "disable naming prefix for variables")
 	generateCmd.Flags().BoolVarP(&disableAttrPrefix, "no-out-prefix", "", false, "disable naming prefix for outputs")
+	generateCmd.Flags().BoolVarP(&disableExamples, "no-examples", "", false, "don't write examples/ of calling the module, derived from the docs' Example Usage")
+	generateCmd.Flags().BoolVarP(&echoOutputs, "echo-outputs", "", false, "also add outputs of the arguments the provider doesn't compute, echoing the module's inputs")
+	generateCmd.Flags().StringVarP(&variableOrder, "variable-order", "", terraform.VariableOrderDocs, "order of the variables and resource arguments: "+strings.Join(terraform.VariableOrders, ", "))
+	generateCmd.Flags().BoolVarP(&groupVariables, "group-variables", "", false, "group the variables and resource arguments under Required, Optional, Nested blocks and Deprecated banners")
+	generateCmd.Flags().BoolVarP(&strict, "strict", "", false, "fail if the docs leave an argument of the schema undocumented, or have entries that can't be parsed")
 
 	generateCmd.Flags().StringVarP(&varPrefix, "variable-prefix", "v", "", "variable prefix (default: <resource_type>_<resource_name>_<variable>)")
 	generateCmd.Flags().StringVarP(&attrPrefix, "output-prefix", "p", "", "output prefix (default: <resource_type>_<resource_name>_<attribute>)")
@@ -59,17 +68,23 @@ func init() {
 	cobra.CheckErr(viper.BindPFlag("license", generateCmd.Flags().Lookup("license")))
 	cobra.CheckErr(viper.BindPFlag("stand-alone", generateCmd.Flags().Lookup("stand-alone")))
 
+	cobra.CheckErr(generateCmd.RegisterFlagCompletionFunc("license", completeLicenses))
+	cobra.CheckErr(generateCmd.RegisterFlagCompletionFunc("variable-order", cobra.FixedCompletions(terraform.VariableOrders, cobra.ShellCompDirectiveNoFileComp)))
+
 	rootCmd.AddCommand(generateCmd)
 }
 
 var generateCmd = &cobra.Command{
-	Use:   "generate [terraform_resource_type]",
-	Args:  cobra.MinimumNArgs(1),
-	Short: "Generate a Terraform Module from the target resource",
+	Use:               "generate [terraform_resource_type]",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completeResourceTypes,
+	Short:             "Generate a Terraform Module from the target resource",
 	Long: `Terrawrap will generate a terraform module given the resource name 
 provided (e.g. aws_secretsmanager_secret).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		resourceType := args[0]
+		cobra.CheckErr(terraform.CheckVariableOrder(variableOrder))
+
 		// Set Variable/Attribute prefixes
 		if !disableVarPrefix && len(varPrefix) == 0 {
 			varPrefix = setVariablePrefix(resourceType, resourceName)
@@ -79,16 +94,28 @@ provided (e.g. aws_secretsmanager_secret).`,
 		}
 
 		// initialize/download relevant docs
-		tfProvider, err := fetchDocProvider(resourceType)
+		tfProvider, err := fetchDocProvider(resourceType, viper.GetString("provider-version"))
 		cobra.CheckErr(err)
+		cobra.CheckErr(checkSchemaVersion(tfProvider))
 
 		// Initialize modules directory / output path
-		module, err := initializeModulesBase(resourceType, resourceName)
+		module, err := initializeModulesBase(resourceType, resourceName, tfProvider)
 		cobra.CheckErr(err)
 
 		// Parse resource from markdown
 		resource, err := parseResource(resourceType, resourceName, varPrefix, attrPrefix, module, tfProvider)
 		cobra.CheckErr(err)
+		if strict {
+			cobra.CheckErr(checkStrict(resource))
+		}
+
+		resource.ImportBlock = importBlock
+		resource.WriteExamples = !disableExamples
+		resource.VariableOrder = variableOrder
+		resource.GroupVariables = groupVariables
+		if importBlock && resource.Import.Example == "" {
+			log.Printf("[WARN] the docs of %s don't give an example import ID", resourceType)
+		}
 
 		cobra.CheckErr(generateResource(resource))
 
@@ -104,54 +131,88 @@ func setAttrPrefix(resourceType, resourceName string) string {
 	return strings.Join([]string{resourceType, resourceName}, "_")
 }
 
-func fetchDocProvider(resourceType string) (*terraform.Provider, error) {
-	var (
-		err                           error
-		providerName, providerDocPath string
-		tfProvider                    *terraform.Provider
-	)
+func fetchDocProvider(resourceType, version string) (*terraform.Provider, error) {
+	providerName, err := providerNameFromType(resourceType)
+	if err != nil {
+		return nil, err
+	}
 
+	return fetchProviderDocs(providerName, version)
+}
+
+// providerNameFromType returns the provider a resource type belongs to,
+// e.g. aws for aws_secretsmanager_secret
+func providerNameFromType(resourceType string) (string, error) {
 	providerTypeFormat := regexp.MustCompile(`(?P<Provider>[A-Za-z0-9]+)_`)
 	subMatches := providerTypeFormat.FindStringSubmatch(resourceType)
 	if len(subMatches) < 1 {
-		return tfProvider, fmt.Errorf("failed to identify provider from resource type. " +
+		return "", fmt.Errorf("failed to identify provider from resource type. " +
 			"ensure the resource type matches the provider format " +
 			"(e.g. aws_secretsmanager_secret)")
 	}
 
-	providerName = subMatches[providerTypeFormat.SubexpIndex("Provider")]
+	providerName := subMatches[providerTypeFormat.SubexpIndex("Provider")]
 	if providerName == "" {
-		return tfProvider, fmt.Errorf("failed to identify provider from resource type. " +
+		return "", fmt.Errorf("failed to identify provider from resource type. " +
 			"ensure the resource type matches the provider format " +
 			"(e.g. aws_secretsmanager_secret)")
 	}
 
-	tfProvider, err = terraform.GetProvider(providerName)
+	return providerName, nil
+}
+
+// fetchProviderDocs returns the provider with its docs ready to read,
+// populating the doc cache first if needed.
+func fetchProviderDocs(providerName, version string) (*terraform.Provider, error) {
+	var (
+		err             error
+		providerDocPath string
+		tfProvider      *terraform.Provider
+	)
+
+	tfProvider, err = terraform.GetProvider(providerName, version)
 	if err != nil {
 		return tfProvider, fmt.Errorf("%w", err)
 	}
+	tfProvider.SetDownloadOptions(downloadOptions())
+
+	// An existing checkout needs no cache at all
+	if dir := viper.GetString("docs-dir"); dir != "" {
+		tfProvider.SetSourceDir(dir)
+		return tfProvider, nil
+	}
 
 	// via terrawrap root command initialization
-	providerDocPath = path.Join(cfgPath, "provider_docs", tfProvider.Name, tfProvider.Version)
+	providerDocPath = docCachePath(tfProvider)
 	tfProvider.SetRootDocPath(providerDocPath)
-	if _, err = os.Stat(providerDocPath); os.IsNotExist(err) {
-		// create directory
-		if err = os.MkdirAll(providerDocPath, 0754); err != nil {
-			return tfProvider, err
-		}
-	} else {
-		var files []os.DirEntry
+	tfProvider.SetChecksum(viper.GetString("docs-sha256"))
+	if tfProvider.DocCacheComplete() {
+		log.Printf("found provider docs in %s, skipping download...", providerDocPath)
+		touchDocCache(tfProvider)
+		return tfProvider, nil
+	}
 
-		files, err = os.ReadDir(providerDocPath)
-		if err != nil {
-			return tfProvider, fmt.Errorf("couldn't read provider docs directory %s: %w", providerDocPath, err)
-		}
+	unlock, err := tfProvider.LockDocCache(viper.GetDuration("docs-lock-timeout"))
+	if err != nil {
+		return tfProvider, err
+	}
+	defer unlockDocCache(unlock)
+
+	// Another process may have populated it while we waited for the lock
+	if tfProvider.DocCacheComplete() {
+		log.Printf("found provider docs in %s, skipping download...", providerDocPath)
+		return tfProvider, nil
+	}
 
-		// Directory exist, files exist; return
-		if len(files) != 0 {
-			log.Printf("found non-empty provider docs directory %s, skipping download...", providerDocPath)
-			return tfProvider, nil
+	if _, err = os.Stat(providerDocPath); err == nil {
+		log.Printf("[WARN] provider docs in %s are incomplete, replacing them...", providerDocPath)
+	}
+
+	if archive := viper.GetString("docs-archive"); archive != "" {
+		if err = tfProvider.ImportDocsArchive(archive); err != nil {
+			return tfProvider, err
 		}
+		return tfProvider, nil
 	}
 
 	if err = tfProvider.DownloadDocs(); err != nil {
@@ -161,6 +222,75 @@ func fetchDocProvider(resourceType string) (*terraform.Provider, error) {
 	return tfProvider, err
 }
 
+// touchDocCache records the cached docs were used, for docs prune.
+func touchDocCache(tfProvider *terraform.Provider) {
+	if err := tfProvider.TouchDocCache(); err != nil {
+		log.Printf("[WARN] %s", err)
+	}
+}
+
+func unlockDocCache(unlock func() error) {
+	if err := unlock(); err != nil {
+		log.Printf("[WARN] %s", err)
+	}
+}
+
+// downloadOptions reads the docs download settings from flags/config.
+func downloadOptions() terraform.DownloadOptions {
+	return terraform.DownloadOptions{
+		MirrorURL: viper.GetString("docs-mirror"),
+		Token:     viper.GetString("docs-token"),
+		CABundle:  viper.GetString("docs-ca-bundle"),
+		Timeout:   viper.GetDuration("docs-timeout"),
+		Retries:   viper.GetInt("docs-retries"),
+		Backoff:   viper.GetDuration("docs-backoff"),
+	}
+}
+
+// docCachePath is where docs for the provider version are kept, e.g.
+// $HOME/.terrawrap/provider_docs/aws/v4.29.0
+func docCachePath(tfProvider *terraform.Provider) string {
+	return path.Join(docCacheDir(), tfProvider.Name, tfProvider.Version)
+}
+
+// docCacheDir holds the cached docs of every provider version.
+func docCacheDir() string {
+	return path.Join(cfgPath, "provider_docs")
+}
+
+// checkSchemaVersion warns when the schemas in use come from a different
+// provider release than the docs, as arguments and attributes drift between
+// releases.
+func checkSchemaVersion(tfProvider *terraform.Provider) error {
+	providerSchema, err := schemaSource().ProviderSchema(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if providerSchema.Version != "" && providerSchema.Version != tfProvider.Version {
+		log.Printf("[WARN] provider schema is from %s %s, but docs are from %s; "+
+			"arguments and attributes may not match the docs", tfProvider.Name, providerSchema.Version, tfProvider.Version)
+	}
+
+	return nil
+}
+
+// checkStrict fails when the docs don't cover the resource: any argument of
+// the schema is undocumented, or any entry couldn't be parsed.
+func checkStrict(resource terraform.TFResource) error {
+	diagnostics := resource.StrictDiagnostics()
+	if len(diagnostics) == 0 {
+		return nil
+	}
+
+	problems := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		problems = append(problems, d.String())
+	}
+
+	return fmt.Errorf("the docs of %s are incomplete:\n%s", resource.Type, strings.Join(problems, "\n"))
+}
+
 // this could easily be extended to generate multiple
 func generateResource(resource terraform.TFResource) error {
 	if err := resource.Create(); err != nil {
@@ -170,10 +300,10 @@ func generateResource(resource terraform.TFResource) error {
 	return nil
 }
 
-func initializeModulesBase(resourceType, resourceName string) (*terraform.Module, error) {
+func initializeModulesBase(resourceType, resourceName string, tfProvider *terraform.Provider) (*terraform.Module, error) {
 	module := &terraform.Module{
 		Copyright:     copyrightLine(), // TODO: allow override
-		TerrawrapLine: terrawrapLine(resourceType, resourceName),
+		TerrawrapLine: terrawrapLine(resourceType, resourceName, tfProvider),
 	}
 
 	wd, err := os.Getwd()
@@ -194,43 +324,56 @@ func initializeModulesBase(resourceType, resourceName string) (*terraform.Module
 	return module, nil
 }
 
-// docerizeResourceType parses and strips the resource type of any prefix or suffix
-// in order to match the filename format used by Hashicorp in their docs.
-func docerizeResourceType(resourceType string) string {
-	re := regexp.MustCompile(`^aws_`)
-	return re.ReplaceAllString(resourceType, "")
-}
-
 // TODO: allow generating multiple types of things if needed
 func parseResource(resourceType, resourceName, varPrefix, attrPrefix string, module *terraform.Module, tfProvider *terraform.Provider) (terraform.TFResource, error) {
-	var (
-		err    error
-		source []byte
-	)
+	var err error
 
-	docerizedResourceType := docerizeResourceType(resourceType)
 	resource := terraform.NewTFResource()
 	resource.Module = module
 	resource.Name = resourceName
 	resource.VarPrefix = varPrefix
 	resource.AttrPrefix = attrPrefix
 	resource.Type = resourceType
-	resource.DocerizedType = docerizedResourceType
-	resource.DocPath = path.Join(tfProvider.DocPath(), fmt.Sprintf("r/%s.html.markdown", docerizedResourceType))
+	resource.DocerizedType = tfProvider.DocName(resourceType)
 	resource.AbsolutePath = module.AbsolutePath
+	resource.EchoOutputs = echoOutputs
 	if standAlone {
 		resource.AbsolutePath = resource.AbsolutePath + fmt.Sprintf("/%s", resourceType)
 	}
 
-	err = resource.SetHashicorpResource()
+	err = resource.SetHashicorpResource(schemaSource())
 	if err != nil {
 		return resource, fmt.Errorf("failed to set hashicorp resource: %w", err)
 	}
 
-	source, err = os.ReadFile(resource.DocPath)
+	// Without docs, the module is generated from the schema alone
+	if err = parseDocs(&resource, tfProvider); err != nil {
+		return resource, err
+	}
+	resource.DiagnoseUndocumented()
+	resource.AppendSchemaEntries()
+
+	return resource, nil
+}
+
+// parseDocs enriches the resource with what its docs say, if it has any.
+func parseDocs(resource *terraform.TFResource, tfProvider *terraform.Provider) error {
+	docPath, err := tfProvider.ResourceDocPath(resource.Type)
+	if err != nil {
+		resource.Diagnose(terraform.Diagnostic{Kind: terraform.DiagnosticMissingDocs, Reason: err.Error()})
+		return nil
+	}
+
+	source, err := os.ReadFile(docPath)
 	if err != nil {
-		return resource, fmt.Errorf("failed to read doc file %s: %w", resource.DocPath, err)
+		resource.Diagnose(terraform.Diagnostic{
+			Kind:    terraform.DiagnosticMissingDocs,
+			DocPath: docPath,
+			Reason:  fmt.Sprintf("failed to read doc file: %s", err),
+		})
+		return nil
 	}
+	resource.DocPath = docPath
 
 	md := goldmark.New(
 		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
@@ -238,23 +381,35 @@ func parseResource(resourceType, resourceName, varPrefix, attrPrefix string, mod
 
 	doc := md.Parser().Parse(text.NewReader(source))
 
-	err = ast.Walk(doc, WalkerFn(source, &resource))
-	if err != nil {
-		return resource, fmt.Errorf("failed to walk document tree: %w", err)
+	if err = ast.Walk(doc, WalkerFn(source, resource)); err != nil {
+		return fmt.Errorf("failed to walk document tree: %w", err)
 	}
 
-	return resource, nil
+	return nil
+}
+
+// docsSubsection is a heading within the Argument or Attributes Reference
+// sections, documenting the fields of a nested block.
+type docsSubsection struct {
+	level int
+	// path of the nested block, or nil if none matched the heading
+	path []string
 }
 
 func WalkerFn(source []byte, resource *terraform.TFResource) ast.Walker {
 	var (
-		h1Found                    bool
-		currentSection, subSection string
+		h1Found        bool
+		currentSection string
+		sectionLevel   int
+		subSections    []docsSubsection
+		importLevel    int
+		exampleLevel   int
+		exampleTitle   string
 	)
 
 	SectionArgumentReference := regexp.MustCompile("argument[s]?[-]+reference")
 	SectionAttributesReference := regexp.MustCompile("attribute[s]?[-]+reference")
-	EntryFormat := regexp.MustCompile(`(?P<Name>[-_A-Za-z0-9]+) - \(?(?P<Optional>Optional|Required)?(?:[, ]+)?(?P<Deprecated>DEPRECATED)?\)?(?:[ ]*)(?P<Description>.*$)`)
+	EntryFormat := regexp.MustCompile("`?(?P<Name>[-_A-Za-z0-9]+)`? - " + `\(?(?P<Optional>Optional|Required)?(?:[, ]+)?(?P<Deprecated>DEPRECATED)?\)?(?:[ ]*)(?P<Description>.*$)`)
 
 	return func(node ast.Node, entering bool) (ast.WalkStatus, error) {
 		if !entering {
@@ -282,56 +437,138 @@ func WalkerFn(source []byte, resource *terraform.TFResource) ast.Walker {
 				}
 			}
 
+			if sectionId == "import" {
+				importLevel = heading.Level
+			} else if heading.Level <= importLevel {
+				importLevel = 0
+			}
+
+			if sectionId == "example-usage" {
+				exampleLevel = heading.Level
+				exampleTitle = ""
+			} else if heading.Level <= exampleLevel {
+				exampleLevel = 0
+			} else if exampleLevel > 0 {
+				exampleTitle = strings.TrimSpace(string(node.Text(source)))
+			}
+
 			// We really only care if we're in the Arguments or Attributes
-			// sections
+			// sections, and the nested blocks documented within them
 			if SectionArgumentReference.MatchString(sectionId) || SectionAttributesReference.MatchString(sectionId) {
 				currentSection = sectionId
-				subSection = ""
+				sectionLevel = heading.Level
+				subSections = nil
+			} else if currentSection != "" && heading.Level > sectionLevel {
+				subSections = enterSubsection(resource, subSections, heading, string(node.Text(source)), source)
 			} else {
-				subSection = sectionId
+				currentSection = ""
+				subSections = nil
 			}
 
 			return ast.WalkContinue, nil
 		}
 
-		// TODO: don't
-		// Skip all sub-sections for now
-		if len(subSection) != 0 {
+		if importLevel > 0 {
+			parseImport(node, source, resource)
+			return ast.WalkContinue, nil
+		}
+
+		if exampleLevel > 0 {
+			parseExample(node, source, resource, exampleTitle)
 			return ast.WalkContinue, nil
 		}
 
+		if currentSection == "" {
+			return ast.WalkContinue, nil
+		}
+
+		// Skip subsections that aren't about a nested block
+		var parent []string
+		if len(subSections) > 0 {
+			if parent = subSections[len(subSections)-1].path; parent == nil {
+				return ast.WalkContinue, nil
+			}
+		}
+
 		// Skip all nodes that aren't list items
 		if node.Parent().Kind() != ast.KindList || node.Kind() != ast.KindListItem {
 			return ast.WalkContinue, nil
 		}
 
-		nodeText := node.Text(source)
-		subMatches := EntryFormat.FindStringSubmatch(string(nodeText))
-		entry := terraform.IOEntry{Resource: resource}
+		// Only top level entries' descriptions mention other arguments
+		rewrite := argumentVariables(resource)
+		if len(parent) > 0 {
+			rewrite = func(code string) string { return code }
+		}
 
-		if len(subMatches) > 1 {
-			var deprecated, optional, ok bool
-			if len(subMatches[EntryFormat.SubexpIndex("Deprecated")]) > 0 {
-				deprecated = true
-			}
+		nodeText := renderInline(node, source, rewrite)
+		subMatches := EntryFormat.FindStringSubmatch(nodeText)
+		entry := terraform.IOEntry{
+			Resource: resource,
+			Parent:   parent,
+			DocLine:  lineOf(node, source),
+			DocText:  rawText(node, source),
+		}
 
-			if len(subMatches[EntryFormat.SubexpIndex("Optional")]) > 0 {
-				optional = true
-			}
+		// Entries we can't make sense of are reported, rather than added
+		// without a name
+		if len(subMatches) <= 1 {
+			resource.Diagnose(terraform.Diagnostic{
+				Kind:   terraform.DiagnosticUnparsedEntry,
+				Line:   entry.DocLine,
+				Text:   entry.DocText,
+				Reason: fmt.Sprintf("failed to parse an entry of %s, so it was skipped.", currentSection),
+			})
+			return ast.WalkContinue, nil
+		}
 
-			entry.Name = subMatches[EntryFormat.SubexpIndex("Name")]
-			entry.Optional = optional
-			entry.Deprecated = deprecated
-			entry.Description = subMatches[EntryFormat.SubexpIndex("Description")]
-			// Per github.com/hashicorp/terraform-provider-aws/internal/helper/schema/resource.go
-			// ~line 1200, ID must always be string and isn't defined in the data sources or resource attributes
-			if entry.Schema, ok = resource.Schema[entry.Name]; !ok && entry.Name != "id" {
-				log.Printf("[WARN] failed to discover schema for %s.%s, so it was omitted. It may be a sub-resource.", currentSection, entry.Name)
-			}
+		var deprecated, optional bool
+		if len(subMatches[EntryFormat.SubexpIndex("Deprecated")]) > 0 {
+			deprecated = true
+		}
+
+		if len(subMatches[EntryFormat.SubexpIndex("Optional")]) > 0 {
+			optional = true
+		}
+
+		entry.Name = subMatches[EntryFormat.SubexpIndex("Name")]
+		entry.Optional = optional
+		entry.Deprecated = deprecated
+		entry.Description = subMatches[EntryFormat.SubexpIndex("Description")]
+		// Per github.com/hashicorp/terraform-provider-aws/internal/helper/schema/resource.go
+		// ~line 1200, ID must always be string and isn't defined in the data sources or resource attributes
+		s, err := resource.NestedSchema(append(append([]string{}, parent...), entry.Name)...)
+		if err == nil {
+			entry.Schema = s
+		} else if entry.Name != "id" {
+			resource.Diagnose(terraform.Diagnostic{
+				Kind:   terraform.DiagnosticNotInSchema,
+				Line:   entry.DocLine,
+				Text:   entry.DocText,
+				Path:   entry.Path(),
+				Reason: fmt.Sprintf("failed to discover schema for %s.%s, so it was omitted.", currentSection, entry.Path()),
+			})
 		}
 
-		// if we're currently in the argument section
 		if SectionArgumentReference.MatchString(currentSection) {
+			entry.Constraint = resource.CheckConstraint(entry, terraform.ParseConstraint(entry.DocText))
+			if docDefault, ok := terraform.ParseDefault(entry.DocText, entry.Schema); ok {
+				entry.DocDefault = resource.CheckDefault(entry, docDefault)
+			}
+		}
+
+		// The schema decides what exists: documented entries it doesn't have
+		// are dropped (but for the implicit id), and documented arguments it
+		// only computes are attributes
+		if entry.Schema == nil && entry.Name != "id" {
+			return ast.WalkContinue, nil
+		}
+		isArgument := SectionArgumentReference.MatchString(currentSection) &&
+			entry.Schema != nil && (entry.Schema.Required || entry.Schema.Optional)
+
+		if len(parent) > 0 {
+			resource.AppendNestedEntry(entry)
+		} else if isArgument {
 			resource.AppendArgument(terraform.InputEntry(entry))
 		} else {
 			resource.AppendAttribute(terraform.OutputEntry(entry))
@@ -341,6 +578,115 @@ func WalkerFn(source []byte, resource *terraform.TFResource) ast.Walker {
 	}
 }
 
+// rawText is the markdown source of a node's lines, e.g. a list item's,
+// with code spans and links intact.
+func rawText(node ast.Node, source []byte) string {
+	var lines []string
+
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		segments := child.Lines()
+		for i := 0; i < segments.Len(); i++ {
+			segment := segments.At(i)
+			lines = append(lines, strings.TrimSpace(string(segment.Value(source))))
+		}
+	}
+
+	return strings.Join(lines, " ")
+}
+
+// lineOf is the line of the source a node starts on, counting from 1, or 0
+// if it has no lines of its own or in its children.
+func lineOf(node ast.Node, source []byte) int {
+	if node.Type() == ast.TypeBlock && node.Lines().Len() > 0 {
+		return bytes.Count(source[:node.Lines().At(0).Start], []byte("\n")) + 1
+	}
+
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		if line := lineOf(child, source); line > 0 {
+			return line
+		}
+	}
+
+	return 0
+}
+
+// importCommand matches the example ID in an Import section's code, given
+// as either `terraform import aws_x.example <id>` or an import block's
+// `id = "<id>"`.
+var importCommand = regexp.MustCompile(`^\s*(?:\$\s*)?terraform import \S+\s+(.+?)\s*$|^\s*id\s*=\s*"(.*)"`)
+
+// parseImport records the ID format described by the Import section: its
+// first paragraph and first example ID.
+func parseImport(node ast.Node, source []byte, resource *terraform.TFResource) {
+	switch node.Kind() {
+	case ast.KindParagraph:
+		if resource.Import.Description == "" && node.Parent().Kind() == ast.KindDocument {
+			resource.Import.Description = strings.TrimSpace(string(node.Text(source)))
+		}
+	case ast.KindFencedCodeBlock, ast.KindCodeBlock:
+		lines := node.Lines()
+		for i := 0; i < lines.Len() && resource.Import.Example == ""; i++ {
+			line := lines.At(i)
+			if subMatches := importCommand.FindStringSubmatch(string(line.Value(source))); subMatches != nil {
+				resource.Import.Example = strings.Trim(subMatches[1]+subMatches[2], `'"`)
+			}
+		}
+	}
+}
+
+// exampleLanguages are the code block languages docs write HCL examples in;
+// others (e.g. CDKTF's typescript) are skipped.
+var exampleLanguages = map[string]bool{"": true, "terraform": true, "hcl": true, "tf": true}
+
+// parseExample records the HCL code blocks of the Example Usage section.
+func parseExample(node ast.Node, source []byte, resource *terraform.TFResource, title string) {
+	codeBlock, ok := node.(*ast.FencedCodeBlock)
+	if !ok || !exampleLanguages[string(codeBlock.Language(source))] {
+		return
+	}
+
+	var hcl strings.Builder
+	lines := codeBlock.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		hcl.Write(line.Value(source))
+	}
+
+	resource.AppendExample(terraform.Example{Title: title, HCL: hcl.String(), Line: lineOf(codeBlock, source)})
+}
+
+// blockHeadingSuffix matches the words docs add to the name of a nested
+// block in its heading, e.g. "replica Configuration Block".
+var blockHeadingSuffix = regexp.MustCompile(`(?i)(\s+(configuration|config|block|blocks|arguments?|attributes?))+$`)
+
+// enterSubsection opens the subsection of a heading, closing any of the
+// same or a deeper level, and matches it to the nested block it documents.
+func enterSubsection(resource *terraform.TFResource, subSections []docsSubsection, heading *ast.Heading, title string, source []byte) []docsSubsection {
+	for len(subSections) > 0 && subSections[len(subSections)-1].level >= heading.Level {
+		subSections = subSections[:len(subSections)-1]
+	}
+
+	var parent []string
+	if len(subSections) > 0 {
+		parent = subSections[len(subSections)-1].path
+	}
+
+	name := strings.TrimSpace(blockHeadingSuffix.ReplaceAllString(strings.TrimSpace(title), ""))
+	name = strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(name))
+
+	path := resource.NestedBlockPath(parent, name)
+	if path == nil {
+		resource.Diagnose(terraform.Diagnostic{
+			Kind:   terraform.DiagnosticUnmatchedSubsection,
+			Line:   lineOf(heading, source),
+			Text:   title,
+			Reason: fmt.Sprintf("failed to match docs subsection %q to a nested block of %s, so it was skipped.", title, resource.Type),
+		})
+	}
+
+	return append(subSections, docsSubsection{level: heading.Level, path: path})
+}
+
 func copyrightLine() string {
 	author := viper.GetString("author")
 
@@ -349,9 +695,10 @@ func copyrightLine() string {
 	return "Copyright © " + year + " " + author
 }
 
-func terrawrapLine(resourceType, resourceName string) string {
+func terrawrapLine(resourceType, resourceName string, tfProvider *terraform.Provider) string {
 	str := fmt.Sprintf(`
 AWS Resource: %s - %s
+Provider: %s %s
 Generated with love by Terrawrap, an InfraCasts, LLC tool!
 https://infracasts.com
 
@@ -359,7 +706,7 @@ The code generated below was generated using MPL v2.0 licensed code
 and documentation, and as such is is subject to the terms of the Mozilla Public
 License, v. 2.0. If a copy of the MPL was not distributed with this file, You
 can obtain one at https://mozilla.org/MPL/2.0/.
-`, resourceType, resourceName)
+`, resourceType, resourceName, tfProvider.RepositoryName, tfProvider.Version)
 	str = strings.TrimSpace(str)
 	return str
 }
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/infracasts/terrawrap-cli/terraform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+var inspectFormat string
+
+func init() {
+	inspectCmd.Flags().StringVarP(&inspectFormat, "format", "f", "json", "output format, json or yaml")
+	cobra.CheckErr(inspectCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"json", "yaml"}, cobra.ShellCompDirectiveNoFileComp)))
+
+	rootCmd.AddCommand(inspectCmd)
+}
+
+var inspectCmd = &cobra.Command{
+	Use:               "inspect [terraform_resource_type]",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeResourceTypes,
+	Short:             "Print the parsed model of a resource",
+	Long: `Terrawrap will print what it parsed from the resource's schema and
+documentation (arguments, attributes, types, defaults, descriptions and
+any diagnostics) as JSON or YAML, instead of generating a module.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		resourceType := args[0]
+		// Nothing is generated, so the resource's local name doesn't matter
+		localName := "default"
+
+		tfProvider, err := fetchDocProvider(resourceType, viper.GetString("provider-version"))
+		cobra.CheckErr(err)
+
+		resource, err := parseResource(resourceType, localName, "", "", &terraform.Module{}, tfProvider)
+		cobra.CheckErr(err)
+
+		model := resource.Model(tfProvider.Version)
+
+		switch inspectFormat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			cobra.CheckErr(encoder.Encode(model))
+		case "yaml":
+			encoder := yaml.NewEncoder(os.Stdout)
+			encoder.SetIndent(2)
+			cobra.CheckErr(encoder.Encode(model))
+			cobra.CheckErr(encoder.Close())
+		default:
+			cobra.CheckErr(fmt.Errorf("unknown format %q, expected json or yaml", inspectFormat))
+		}
+	},
+}
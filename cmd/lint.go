@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/infracasts/terrawrap-cli/terraform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// lintFindingsExitCode is the exit code of lint-docs when it finds gaps (or,
+// with --baseline, more than before), telling them apart from failures.
+const lintFindingsExitCode = 2
+
+var lintService, lintFormat, lintBaseline string
+
+func init() {
+	lintDocsCmd.Flags().StringVar(&lintService, "service", "", "only audit resources of this service (e.g. secretsmanager for aws_secretsmanager_*)")
+	lintDocsCmd.Flags().StringVarP(&lintFormat, "format", "f", "table", "output format, table or json")
+	lintDocsCmd.Flags().StringVar(&lintBaseline, "baseline", "", "JSON report of an earlier run; only resources with more problems than in it are findings")
+	cobra.CheckErr(lintDocsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"table", "json"}, cobra.ShellCompDirectiveNoFileComp)))
+
+	rootCmd.AddCommand(lintDocsCmd)
+}
+
+// lintReport is the JSON output of lint-docs, and what --baseline reads.
+type lintReport struct {
+	Provider    string               `json:"provider"`
+	DocsVersion string               `json:"docs_version"`
+	Resources   []terraform.Coverage `json:"resources"`
+}
+
+var lintDocsCmd = &cobra.Command{
+	Use:   "lint-docs [provider]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Audit how well the docs of every resource cover its schema",
+	Long: `Terrawrap will parse the docs of every resource type of the provider
+(default: aws), and report for each the schema fields missing from its docs,
+doc entries missing from its schema, list items it couldn't parse, and nested
+blocks none of whose fields were parsed.
+
+It exits with 2 if any resource has problems, or with --baseline, if any has
+more problems than in the given report of an earlier run (e.g. before bumping
+the provider version).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		providerName := "aws"
+		if len(args) > 0 {
+			providerName = args[0]
+		}
+
+		if lintFormat != "table" && lintFormat != "json" {
+			cobra.CheckErr(fmt.Errorf("unknown format %q, expected table or json", lintFormat))
+		}
+
+		var baseline *lintReport
+		if lintBaseline != "" {
+			var err error
+			baseline, err = readLintReport(lintBaseline)
+			cobra.CheckErr(err)
+		}
+
+		tfProvider, err := fetchProviderDocs(providerName, viper.GetString("provider-version"))
+		cobra.CheckErr(err)
+
+		report, err := lintDocs(tfProvider)
+		cobra.CheckErr(err)
+
+		switch lintFormat {
+		case "json":
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			cobra.CheckErr(encoder.Encode(report))
+		case "table":
+			cobra.CheckErr(printLintReport(os.Stdout, report))
+		}
+
+		if findings := lintFindings(report, baseline); len(findings) > 0 {
+			if baseline != nil {
+				fmt.Fprintf(os.Stderr, "%d resources have more problems than in %s:\n  %s\n", len(findings), lintBaseline, strings.Join(findings, "\n  "))
+			}
+			os.Exit(lintFindingsExitCode)
+		}
+	},
+}
+
+// lintDocs audits the docs of each of the provider's resources.
+func lintDocs(tfProvider *terraform.Provider) (lintReport, error) {
+	report := lintReport{Provider: tfProvider.Name, DocsVersion: tfProvider.Version, Resources: []terraform.Coverage{}}
+
+	providerSchema, err := schemaSource().ProviderSchema(context.Background())
+	if err != nil {
+		return report, err
+	}
+
+	servicePrefix := tfProvider.Name + "_" + lintService
+	names := make([]string, 0, len(providerSchema.Resources))
+	for name := range providerSchema.Resources {
+		if lintService != "" && name != servicePrefix && !strings.HasPrefix(name, servicePrefix+"_") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Nothing is generated, so the resources' local name doesn't matter
+	localName := "default"
+
+	// Every problem is in the report, so parsing needn't log them too
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	for _, name := range names {
+		resource, err := parseResource(name, localName, "", "", &terraform.Module{}, tfProvider)
+		coverage := resource.Coverage()
+		if err != nil {
+			coverage.Error = err.Error()
+		}
+		report.Resources = append(report.Resources, coverage)
+	}
+
+	return report, nil
+}
+
+func printLintReport(out io.Writer, report lintReport) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tMISSING FROM DOCS\tMISSING FROM SCHEMA\tUNPARSED\tLOST BLOCKS\tERROR")
+
+	covered := 0
+	for _, c := range report.Resources {
+		if c.Problems() == 0 {
+			covered++
+		}
+		// the paths tried when there are no docs don't fit in a table
+		errorLine := strings.TrimSuffix(strings.SplitN(c.Error, "\n", 2)[0], ", tried:")
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%s\n", c.Type, len(c.MissingFromDocs), len(c.MissingFromSchema), len(c.Unparsed), len(c.LostBlocks), errorLine)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(out, "\n%d of %d resources of %s %s are fully covered by their docs\n", covered, len(report.Resources), report.Provider, report.DocsVersion)
+	return err
+}
+
+// lintFindings are the resources with problems, or with a baseline, those
+// with more problems than in it (including ones it doesn't have).
+func lintFindings(report lintReport, baseline *lintReport) []string {
+	before := make(map[string]int)
+	if baseline != nil {
+		for _, c := range baseline.Resources {
+			before[c.Type] = c.Problems()
+		}
+	}
+
+	var findings []string
+	for _, c := range report.Resources {
+		if c.Problems() > before[c.Type] {
+			findings = append(findings, fmt.Sprintf("%s (%d, was %d)", c.Type, c.Problems(), before[c.Type]))
+		}
+	}
+
+	return findings
+}
+
+func readLintReport(path string) (*lintReport, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var report lintReport
+	if err := json.Unmarshal(b, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/infracasts/terrawrap-cli/terraform"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var listService string
+
+func init() {
+	listCmd.Flags().StringVar(&listService, "service", "", "only list types of this service (e.g. secretsmanager for aws_secretsmanager_*)")
+
+	rootCmd.AddCommand(listCmd)
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list [provider]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "List the resources and data sources a provider offers",
+	Long: `Terrawrap will list every resource and data source type of the provider
+(default: aws), noting which have docs in the cache and which are deprecated.
+
+Docs are only looked up in the cache (or --docs-dir); nothing is downloaded.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		providerName := "aws"
+		if len(args) > 0 {
+			providerName = args[0]
+		}
+
+		tfProvider, cached, err := cachedDocProvider(providerName, viper.GetString("provider-version"))
+		cobra.CheckErr(err)
+		if !cached {
+			log.Printf("[WARN] no complete docs cached for %s %s; run terrawrap generate or terrawrap docs import to fetch them", tfProvider.Name, tfProvider.Version)
+		}
+
+		providerSchema, err := schemaSource().ProviderSchema(context.Background())
+		cobra.CheckErr(err)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tKIND\tDOCS\tDEPRECATED")
+		listTypes(w, tfProvider, cached, providerSchema.Resources, "resource", tfProvider.ResourceDocPath)
+		listTypes(w, tfProvider, cached, providerSchema.DataSources, "data source", tfProvider.DataSourceDocPath)
+		cobra.CheckErr(w.Flush())
+	},
+}
+
+// cachedDocProvider returns the provider with whichever docs are already
+// available, without downloading any, and whether there are any. The
+// remains of an interrupted download don't count.
+func cachedDocProvider(providerName, version string) (*terraform.Provider, bool, error) {
+	tfProvider, err := terraform.GetProvider(providerName, version)
+	if err != nil {
+		return tfProvider, false, err
+	}
+
+	if dir := viper.GetString("docs-dir"); dir != "" {
+		tfProvider.SetSourceDir(dir)
+		return tfProvider, true, nil
+	}
+
+	tfProvider.SetRootDocPath(docCachePath(tfProvider))
+
+	return tfProvider, tfProvider.DocCacheComplete(), nil
+}
+
+func listTypes(w *tabwriter.Writer, tfProvider *terraform.Provider, cached bool, types map[string]*schema.Resource, kind string, docPath func(string) (string, error)) {
+	servicePrefix := tfProvider.Name + "_" + listService
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		if listService != "" && name != servicePrefix && !strings.HasPrefix(name, servicePrefix+"_") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		docs := "no"
+		if _, err := docPath(name); cached && err == nil {
+			docs = "yes"
+		}
+
+		deprecated := ""
+		if types[name].DeprecationMessage != "" {
+			deprecated = "yes"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, kind, docs, deprecated)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"html"
+	"strings"
+
+	"github.com/infracasts/terrawrap-cli/terraform"
+	"github.com/yuin/goldmark/ast"
+)
+
+// renderInline renders a node's inline markdown as plain text, unlike
+// node.Text: code spans keep their backticks, links keep their target as
+// "text (url)", HTML entities are decoded and raw HTML is dropped.
+//
+// rewrite is applied to the content of each code span, e.g. to rename an
+// argument to its variable.
+func renderInline(node ast.Node, source []byte, rewrite func(code string) string) string {
+	var b strings.Builder
+	renderNode(&b, node, source, rewrite)
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func renderNode(b *strings.Builder, node ast.Node, source []byte, rewrite func(code string) string) {
+	switch n := node.(type) {
+	case *ast.Text:
+		b.WriteString(html.UnescapeString(string(n.Segment.Value(source))))
+		if n.SoftLineBreak() || n.HardLineBreak() {
+			b.WriteByte(' ')
+		}
+	case *ast.String:
+		b.WriteString(html.UnescapeString(string(n.Value)))
+	case *ast.CodeSpan:
+		b.WriteString("`" + rewrite(string(n.Text(source))) + "`")
+	case *ast.Link:
+		var text strings.Builder
+		renderChildren(&text, n, source, rewrite)
+
+		destination := string(n.Destination)
+		switch {
+		// anchors within the page mean nothing outside of it
+		case strings.HasPrefix(destination, "#"), destination == text.String():
+			b.WriteString(text.String())
+		default:
+			b.WriteString(text.String() + " (" + destination + ")")
+		}
+	case *ast.AutoLink:
+		b.Write(n.URL(source))
+	case *ast.RawHTML:
+	default:
+		renderChildren(b, n, source, rewrite)
+		// keep block children (e.g. paragraphs of a list item) apart
+		if n.Type() == ast.TypeBlock {
+			b.WriteByte(' ')
+		}
+	}
+}
+
+func renderChildren(b *strings.Builder, node ast.Node, source []byte, rewrite func(code string) string) {
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		renderNode(b, child, source, rewrite)
+	}
+}
+
+// argumentVariables renames code spans naming one of the resource's
+// arguments to its variable, e.g. `name_prefix` in "Conflicts with
+// `name_prefix`", except the first, which is the documented entry's own
+// name. Nothing is renamed without a variable prefix.
+func argumentVariables(resource *terraform.TFResource) func(code string) string {
+	first := true
+
+	return func(code string) string {
+		if first {
+			first = false
+			return code
+		}
+
+		if resource.VarPrefix == "" || resource.Resource == nil {
+			return code
+		}
+
+		if s, ok := resource.Schema[code]; ok && (s.Required || s.Optional) {
+			return terraform.InputEntry{Resource: resource, Name: code}.PrefixedName()
+		}
+
+		return code
+	}
+}
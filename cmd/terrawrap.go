@@ -4,13 +4,20 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"time"
 
+	"github.com/infracasts/terrawrap-cli/terraform"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile, cfgPath string
+	cfgFile, cfgPath, providerBinary, providerVersion, docsDir, docsArchive string
+	docsMirror, docsCABundle, docsSHA256                                    string
+	docsTimeout, docsBackoff, docsLockTimeout                               time.Duration
+	docsRetries                                                             int
+
+	tfSchemaSource terraform.SchemaSource
 
 	rootCmd = &cobra.Command{
 		Use:   "terrawrap",
@@ -31,6 +38,36 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.terrawrap/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&providerBinary, "provider-binary", "", "provider plugin binary to read schemas from, e.g. from .terraform/providers (default: the provider built into terrawrap)")
+
+	rootCmd.PersistentFlags().StringVar(&providerVersion, "provider-version", "", "provider release to use the documentation of, e.g. v4.30.0 (default: the version terrawrap was built against)")
+	rootCmd.PersistentFlags().StringVar(&docsDir, "docs-dir", "", "read docs from an existing checkout of the provider repository instead of downloading them")
+	rootCmd.PersistentFlags().StringVar(&docsArchive, "docs-archive", "", "populate the docs cache from a local zip of the provider repository instead of downloading it")
+	rootCmd.PersistentFlags().StringVar(&docsSHA256, "docs-sha256", "", "SHA-256 the downloaded or imported docs archive must have")
+
+	// Downloads; the token is deliberately config/environment only
+	defaults := terraform.DefaultDownloadOptions()
+	rootCmd.PersistentFlags().StringVar(&docsMirror, "docs-mirror", "", "base URL to download provider repository archives from instead of https://github.com/hashicorp (e.g. an Artifactory or GitHub Enterprise mirror)")
+	rootCmd.PersistentFlags().StringVar(&docsCABundle, "docs-ca-bundle", "", "PEM file of additional certificate authorities to trust when downloading docs")
+	rootCmd.PersistentFlags().DurationVar(&docsTimeout, "docs-timeout", defaults.Timeout, "timeout for each docs download attempt")
+	rootCmd.PersistentFlags().IntVar(&docsRetries, "docs-retries", defaults.Retries, "number of times to retry a failed docs download")
+	rootCmd.PersistentFlags().DurationVar(&docsBackoff, "docs-backoff", defaults.Backoff, "wait before retrying a failed docs download, doubled on each retry")
+	rootCmd.PersistentFlags().DurationVar(&docsLockTimeout, "docs-lock-timeout", 15*time.Minute, "how long to wait for another terrawrap process populating the same docs cache")
+
+	cobra.CheckErr(viper.BindPFlag("provider-binary", rootCmd.PersistentFlags().Lookup("provider-binary")))
+	cobra.CheckErr(viper.BindPFlag("provider-version", rootCmd.PersistentFlags().Lookup("provider-version")))
+	cobra.CheckErr(viper.BindPFlag("docs-dir", rootCmd.PersistentFlags().Lookup("docs-dir")))
+	cobra.CheckErr(viper.BindPFlag("docs-archive", rootCmd.PersistentFlags().Lookup("docs-archive")))
+	cobra.CheckErr(viper.BindPFlag("docs-sha256", rootCmd.PersistentFlags().Lookup("docs-sha256")))
+	cobra.CheckErr(viper.BindPFlag("docs-mirror", rootCmd.PersistentFlags().Lookup("docs-mirror")))
+	cobra.CheckErr(viper.BindPFlag("docs-ca-bundle", rootCmd.PersistentFlags().Lookup("docs-ca-bundle")))
+	cobra.CheckErr(viper.BindPFlag("docs-timeout", rootCmd.PersistentFlags().Lookup("docs-timeout")))
+	cobra.CheckErr(viper.BindPFlag("docs-retries", rootCmd.PersistentFlags().Lookup("docs-retries")))
+	cobra.CheckErr(viper.BindPFlag("docs-backoff", rootCmd.PersistentFlags().Lookup("docs-backoff")))
+	cobra.CheckErr(viper.BindPFlag("docs-lock-timeout", rootCmd.PersistentFlags().Lookup("docs-lock-timeout")))
+	cobra.CheckErr(viper.BindEnv("docs-token", "TERRAWRAP_DOCS_TOKEN"))
+
+	cobra.CheckErr(rootCmd.RegisterFlagCompletionFunc("provider-version", completeProviderVersions))
 }
 
 func initConfig() {
@@ -53,7 +90,22 @@ func initConfig() {
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 }
 
+// schemaSource returns the source of provider schemas, launching the
+// configured provider binary if there is one.
+func schemaSource() terraform.SchemaSource {
+	if tfSchemaSource != nil {
+		return tfSchemaSource
+	}
+
+	if binary := viper.GetString("provider-binary"); binary != "" {
+		tfSchemaSource = terraform.NewPluginSchemaSource(binary)
+	} else {
+		tfSchemaSource = terraform.NewEmbeddedSchemaSource()
+	}
+
+	return tfSchemaSource
+}
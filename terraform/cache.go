@@ -0,0 +1,238 @@
+package terraform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// docCacheMarker is written to a root doc path once it's fully populated;
+// anything without one is the remains of an interrupted download.
+const docCacheMarker = ".terrawrap-complete"
+
+// DocCacheManifest records where a root doc path was populated from.
+type DocCacheManifest struct {
+	Provider  string    `json:"provider"`
+	Version   string    `json:"version"`
+	Source    string    `json:"source"`
+	SHA256    string    `json:"sha256,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DocCacheComplete reports whether the root doc path was fully populated.
+func (p *Provider) DocCacheComplete() bool {
+	_, err := p.DocCacheManifest()
+	return err == nil
+}
+
+// DocCacheManifest reads the completion marker of the root doc path.
+func (p *Provider) DocCacheManifest() (*DocCacheManifest, error) {
+	markerPath := filepath.Join(p.rootDocPath, docCacheMarker)
+
+	b, err := os.ReadFile(markerPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read doc cache marker %s: %w", markerPath, err)
+	}
+
+	var manifest DocCacheManifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse doc cache marker %s: %w", markerPath, err)
+	}
+
+	return &manifest, nil
+}
+
+// TouchDocCache records that the root doc path was just used, by updating
+// the modification time of its completion marker.
+func (p *Provider) TouchDocCache() error {
+	markerPath := filepath.Join(p.rootDocPath, docCacheMarker)
+	now := time.Now()
+
+	if err := os.Chtimes(markerPath, now, now); err != nil {
+		return fmt.Errorf("failed to record use of doc cache %s: %w", p.rootDocPath, err)
+	}
+
+	return nil
+}
+
+// RemoveDocCache deletes the root doc path.
+func (p *Provider) RemoveDocCache() error {
+	if err := os.RemoveAll(p.rootDocPath); err != nil {
+		return fmt.Errorf("failed to remove provider docs %s: %w", p.rootDocPath, err)
+	}
+
+	return nil
+}
+
+// CachedDocs describes one provider version in the doc cache.
+type CachedDocs struct {
+	Provider string
+	Version  string
+	Path     string
+	Size     int64
+	Complete bool
+	// LastUsed is when the docs were last read, or populated.
+	LastUsed time.Time
+}
+
+// ListDocCache returns every provider version under the doc cache
+// directory (e.g. $HOME/.terrawrap/provider_docs), laid out as
+// <provider>/<version>, ordered by provider then most recently used.
+func ListDocCache(cacheDir string) ([]CachedDocs, error) {
+	var cached []CachedDocs
+
+	providers, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return cached, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("couldn't read provider docs directory %s: %w", cacheDir, err)
+	}
+
+	for _, providerDir := range providers {
+		if !providerDir.IsDir() {
+			continue
+		}
+
+		versions, err := os.ReadDir(filepath.Join(cacheDir, providerDir.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read provider docs directory %s: %w", providerDir.Name(), err)
+		}
+
+		for _, versionDir := range versions {
+			// skip lock files and staging directories
+			if !versionDir.IsDir() || strings.HasPrefix(versionDir.Name(), ".") {
+				continue
+			}
+
+			docs, err := cachedDocs(cacheDir, providerDir.Name(), versionDir.Name())
+			if err != nil {
+				return nil, err
+			}
+			cached = append(cached, docs)
+		}
+	}
+
+	sort.SliceStable(cached, func(i, j int) bool {
+		if cached[i].Provider != cached[j].Provider {
+			return cached[i].Provider < cached[j].Provider
+		}
+		return cached[i].LastUsed.After(cached[j].LastUsed)
+	})
+
+	return cached, nil
+}
+
+func cachedDocs(cacheDir, providerName, version string) (CachedDocs, error) {
+	docs := CachedDocs{
+		Provider: providerName,
+		Version:  version,
+		Path:     filepath.Join(cacheDir, providerName, version),
+	}
+
+	info, err := os.Stat(filepath.Join(docs.Path, docCacheMarker))
+	if err == nil {
+		docs.Complete = true
+		docs.LastUsed = info.ModTime()
+	} else if info, err = os.Stat(docs.Path); err == nil {
+		docs.LastUsed = info.ModTime()
+	} else {
+		return docs, fmt.Errorf("failed to stat %s: %w", docs.Path, err)
+	}
+
+	err = filepath.WalkDir(docs.Path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		docs.Size += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return docs, fmt.Errorf("failed to measure %s: %w", docs.Path, err)
+	}
+
+	return docs, nil
+}
+
+// stageDocs populates a staging directory next to the root doc path, marks
+// it complete, then renames it into place, so the root doc path is only ever
+// missing or complete.
+func (p *Provider) stageDocs(source, sum string, populate func(rootDocPath string) error) error {
+	parent := filepath.Dir(p.rootDocPath)
+	if err := os.MkdirAll(parent, 0754); err != nil {
+		return fmt.Errorf("failed to create provider docs directory %s: %w", parent, err)
+	}
+
+	staging, err := os.MkdirTemp(parent, "."+filepath.Base(p.rootDocPath)+".staging-")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory in %s: %w", parent, err)
+	}
+	// a no-op once renamed into place
+	defer os.RemoveAll(staging)
+
+	if err := os.Chmod(staging, 0754); err != nil {
+		return fmt.Errorf("failed to set permissions of staging directory %s: %w", staging, err)
+	}
+
+	if err := populate(staging); err != nil {
+		return err
+	}
+
+	manifest, err := json.MarshalIndent(DocCacheManifest{
+		Provider:  p.Name,
+		Version:   p.Version,
+		Source:    source,
+		SHA256:    sum,
+		CreatedAt: time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode doc cache marker: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(staging, docCacheMarker), manifest, 0644); err != nil {
+		return fmt.Errorf("failed to write doc cache marker: %w", err)
+	}
+
+	// Replace whatever was there before, e.g. an earlier partial download
+	if err := os.RemoveAll(p.rootDocPath); err != nil {
+		return fmt.Errorf("failed to remove previous provider docs %s: %w", p.rootDocPath, err)
+	}
+
+	if err := os.Rename(staging, p.rootDocPath); err != nil {
+		return fmt.Errorf("failed to move staged provider docs into %s: %w", p.rootDocPath, err)
+	}
+
+	return nil
+}
+
+// LockDocCache takes an exclusive advisory lock on the root doc path, shared
+// with other terrawrap processes, so only one of them populates it at a time.
+// It waits up to timeout for the lock; call unlock once done.
+func (p *Provider) LockDocCache(timeout time.Duration) (unlock func() error, err error) {
+	parent := filepath.Dir(p.rootDocPath)
+	if err := os.MkdirAll(parent, 0754); err != nil {
+		return nil, fmt.Errorf("failed to create provider docs directory %s: %w", parent, err)
+	}
+
+	lockPath := filepath.Join(parent, "."+filepath.Base(p.rootDocPath)+".lock")
+
+	lock, err := lockFile(lockPath, timeout)
+	if errors.Is(err, errLocked) {
+		return nil, fmt.Errorf("timed out after %s waiting for another terrawrap process to finish "+
+			"populating %s (lock file %s); try again, or raise --docs-lock-timeout", timeout, p.rootDocPath, lockPath)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return lock.Unlock, nil
+}
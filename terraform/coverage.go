@@ -0,0 +1,159 @@
+package terraform
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Coverage is how completely a resource's docs cover its schema, as audited
+// by terrawrap lint-docs. Its field names are kept stable, so reports of
+// different provider versions can be compared.
+type Coverage struct {
+	Type    string `json:"type"`
+	DocPath string `json:"doc_path"`
+	// Error is why the docs couldn't be parsed at all, e.g. there are none.
+	Error string `json:"error,omitempty"`
+	// MissingFromDocs are the schema's top level fields the docs have no
+	// entry for.
+	MissingFromDocs []string `json:"missing_from_docs"`
+	// MissingFromSchema are the paths of documented entries the schema
+	// doesn't have.
+	MissingFromSchema []string `json:"missing_from_schema"`
+	// Unparsed are the docs' list items that couldn't be parsed.
+	Unparsed []Diagnostic `json:"unparsed"`
+	// LostBlocks are the paths of the schema's nested blocks none of whose
+	// fields were parsed from the docs.
+	LostBlocks []string `json:"lost_blocks"`
+}
+
+// Problems is the number of gaps between the docs and the schema; an error
+// counts as one.
+func (c Coverage) Problems() int {
+	problems := len(c.MissingFromDocs) + len(c.MissingFromSchema) + len(c.Unparsed) + len(c.LostBlocks)
+	if c.Error != "" {
+		problems++
+	}
+	return problems
+}
+
+// Coverage audits how completely the parsed docs cover the resource's
+// schema.
+func (r TFResource) Coverage() Coverage {
+	c := Coverage{
+		Type:              r.Type,
+		DocPath:           r.DocPath,
+		MissingFromDocs:   []string{},
+		MissingFromSchema: []string{},
+		Unparsed:          []Diagnostic{},
+		LostBlocks:        []string{},
+	}
+
+	// entries the schema doesn't have are dropped while parsing, so only
+	// their diagnostics are left
+	notInSchema := make(map[string]bool)
+	for _, d := range r.diagnostics {
+		switch d.Kind {
+		case DiagnosticMissingDocs:
+			c.Error = d.Reason
+		case DiagnosticUnparsedEntry:
+			c.Unparsed = append(c.Unparsed, d)
+		case DiagnosticNotInSchema:
+			if !notInSchema[d.Path] {
+				notInSchema[d.Path] = true
+				c.MissingFromSchema = append(c.MissingFromSchema, d.Path)
+			}
+		}
+	}
+	sort.Strings(c.MissingFromSchema)
+
+	if r.Resource == nil {
+		return c
+	}
+
+	documented := r.documentedPaths()
+	for _, name := range sortedFieldNames(r.Schema) {
+		if !documented[name] {
+			c.MissingFromDocs = append(c.MissingFromDocs, name)
+		}
+	}
+
+	c.LostBlocks = lostBlocks(r.Schema, nil, documented)
+
+	return c
+}
+
+// entries are the resource's arguments, attributes and fields of nested
+// blocks.
+func (r TFResource) entries() []IOEntry {
+	entries := append([]IOEntry{}, r.nested.Entries...)
+	for _, argument := range r.Arguments() {
+		entries = append(entries, IOEntry(argument))
+	}
+	for _, attribute := range r.Attributes() {
+		entries = append(entries, IOEntry(attribute))
+	}
+
+	return entries
+}
+
+// documentedPaths are the paths of the entries parsed from the docs, in
+// either section, e.g. name and rotation_rules.automatically_after_days.
+func (r TFResource) documentedPaths() map[string]bool {
+	if r.docPaths != nil {
+		return r.docPaths
+	}
+
+	documented := make(map[string]bool)
+	for _, entry := range r.entries() {
+		if entry.Documented() {
+			documented[entry.Path()] = true
+		}
+	}
+
+	return documented
+}
+
+// lostBlocks finds the nested blocks of fields without any documented field,
+// not looking into the blocks of those that are lost.
+func lostBlocks(fields map[string]*schema.Schema, parent []string, documented map[string]bool) []string {
+	lost := []string{}
+
+	for _, name := range sortedFieldNames(fields) {
+		block, ok := fields[name].Elem.(*schema.Resource)
+		if !ok || len(block.Schema) == 0 {
+			continue
+		}
+
+		path := append(append([]string{}, parent...), name)
+		prefix := strings.Join(path, ".") + "."
+
+		found := false
+		for entryPath := range documented {
+			if strings.HasPrefix(entryPath, prefix) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			lost = append(lost, strings.Join(path, "."))
+			continue
+		}
+
+		lost = append(lost, lostBlocks(block.Schema, path, documented)...)
+	}
+
+	return lost
+}
+
+func sortedFieldNames(fields map[string]*schema.Schema) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,116 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	everything := []string{"name", "description", "rotation_rules", "rotation_rules.automatically_after_days", "arn"}
+
+	tests := []struct {
+		name       string
+		documented []string
+		// attributes are documented in the attributes section, whatever the
+		// schema says they are
+		attributes  []string
+		diagnostics []Diagnostic
+		want        Coverage
+		problems    int
+	}{
+		{
+			name:       "complete docs",
+			documented: everything,
+			want:       Coverage{},
+		},
+		{
+			name:       "undocumented fields",
+			documented: []string{"name", "rotation_rules", "rotation_rules.automatically_after_days"},
+			want:       Coverage{MissingFromDocs: []string{"arn", "description"}},
+			problems:   2,
+		},
+		{
+			name:       "documented attributes the schema doesn't compute",
+			documented: []string{"name", "rotation_rules", "rotation_rules.automatically_after_days", "arn"},
+			attributes: []string{"description"},
+			want:       Coverage{},
+		},
+		{
+			name:       "lost nested block",
+			documented: []string{"name", "description", "rotation_rules", "arn"},
+			want:       Coverage{LostBlocks: []string{"rotation_rules"}},
+			problems:   1,
+		},
+		{
+			name:       "entries not in the schema",
+			documented: everything,
+			diagnostics: []Diagnostic{
+				{Kind: DiagnosticNotInSchema, Path: "replica.region"},
+				{Kind: DiagnosticNotInSchema, Path: "bogus"},
+				// documented in both sections
+				{Kind: DiagnosticNotInSchema, Path: "bogus"},
+			},
+			want:     Coverage{MissingFromSchema: []string{"bogus", "replica.region"}},
+			problems: 2,
+		},
+		{
+			name:        "unparsed entries",
+			documented:  everything,
+			diagnostics: []Diagnostic{{Kind: DiagnosticUnparsedEntry, Line: 42, Text: "description is weird"}},
+			want:        Coverage{Unparsed: []Diagnostic{{Kind: DiagnosticUnparsedEntry, Line: 42, Text: "description is weird"}}},
+			problems:    1,
+		},
+		{
+			name:        "missing docs",
+			diagnostics: []Diagnostic{{Kind: DiagnosticMissingDocs, Reason: "failed to find docs"}},
+			want: Coverage{
+				Error:           "failed to find docs",
+				MissingFromDocs: []string{"arn", "description", "name", "rotation_rules"},
+				LostBlocks:      []string{"rotation_rules"},
+			},
+			problems: 6,
+		},
+		{
+			name:        "other diagnostics",
+			documented:  everything,
+			diagnostics: []Diagnostic{{Kind: DiagnosticMismatch, Path: "name"}, {Kind: DiagnosticExample}},
+			want:        Coverage{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testDocumentedResource(t, tt.documented...)
+			for _, name := range tt.attributes {
+				r.AppendAttribute(OutputEntry{Schema: r.Schema[name], Resource: r, Name: name, DocLine: len(tt.documented) + 1})
+			}
+			r.AppendSchemaEntries()
+			for _, d := range tt.diagnostics {
+				d.DocPath = r.DocPath
+				r.diagnostics = append(r.diagnostics, d)
+			}
+
+			want := Coverage{
+				Type:              r.Type,
+				DocPath:           r.DocPath,
+				Error:             tt.want.Error,
+				MissingFromDocs:   append([]string{}, tt.want.MissingFromDocs...),
+				MissingFromSchema: append([]string{}, tt.want.MissingFromSchema...),
+				Unparsed:          []Diagnostic{},
+				LostBlocks:        append([]string{}, tt.want.LostBlocks...),
+			}
+			for _, d := range tt.want.Unparsed {
+				d.DocPath = r.DocPath
+				want.Unparsed = append(want.Unparsed, d)
+			}
+
+			got := r.Coverage()
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Coverage() = %+v, want %+v", got, want)
+			}
+			if got.Problems() != tt.problems {
+				t.Errorf("Problems() = %d, want %d", got.Problems(), tt.problems)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package terraform
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	DefaultSourceSchema = "schema"
+	DefaultSourceDocs   = "docs"
+)
+
+// e.g. "Defaults to `30`", "Default: `false`" or "The default value is 30."
+var defaultFormat = regexp.MustCompile("(?i)(?:defaults to|default(?: value)? is|default:)\\s+(?:`([^`]+)`|(true|false|-?[0-9]+(?:\\.[0-9]+)?)\\b)")
+
+// ParseDefault extracts the default an argument's raw markdown description
+// states, as an HCL literal of the schema's type. Defaults that aren't
+// literals (e.g. "Defaults to the provider region") are ignored.
+func ParseDefault(description string, s *schema.Schema) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+
+	subMatches := defaultFormat.FindStringSubmatch(description)
+	if subMatches == nil {
+		return "", false
+	}
+	value := subMatches[1] + subMatches[2]
+
+	switch s.Type {
+	case schema.TypeBool:
+		if b, err := strconv.ParseBool(strings.ToLower(value)); err == nil {
+			return strconv.FormatBool(b), true
+		}
+	case schema.TypeInt, schema.TypeFloat:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return formatNumber(f), true
+		}
+	case schema.TypeString:
+		// a quoted code span, e.g. `"GP2"`, is still just the string
+		return strconv.Quote(strings.Trim(value, `"`)), true
+	}
+
+	return "", false
+}
+
+// CheckDefault reconciles the default the docs state with the schema's: the
+// schema's wins, and a mismatch is reported. It returns the docs' default to
+// use, if the schema has none.
+func (r *TFResource) CheckDefault(entry IOEntry, docDefault string) string {
+	if docDefault == "" {
+		return ""
+	}
+
+	schemaDefault := entry.schemaDefault()
+	if schemaDefault == "" {
+		return docDefault
+	}
+
+	if schemaDefault != docDefault {
+		r.diagnoseEntry(DiagnosticMismatch, entry, "docs of %s.%s say it defaults to %s, but the schema's default is %s, so the schema's was used.", r.Type, entry.Path(), docDefault, schemaDefault)
+	}
+
+	return ""
+}
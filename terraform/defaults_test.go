@@ -0,0 +1,151 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestParseDefault(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		schema      *schema.Schema
+		want        string
+		wantOK      bool
+	}{
+		{
+			name:        "no schema",
+			description: "Defaults to `30`.",
+		},
+		{
+			name:        "no default",
+			description: "Description of the secret.",
+			schema:      &schema.Schema{Type: schema.TypeString},
+		},
+		{
+			name:        "number in a code span",
+			description: "Number of days. Defaults to `30`.",
+			schema:      &schema.Schema{Type: schema.TypeInt},
+			want:        "30",
+			wantOK:      true,
+		},
+		{
+			name:        "bare number",
+			description: "The default value is 30.",
+			schema:      &schema.Schema{Type: schema.TypeInt},
+			want:        "30",
+			wantOK:      true,
+		},
+		{
+			name:        "float",
+			description: "Default: `0.5`",
+			schema:      &schema.Schema{Type: schema.TypeFloat},
+			want:        "0.5",
+			wantOK:      true,
+		},
+		{
+			name:        "bool",
+			description: "Whether to force deletion. Defaults to `False`.",
+			schema:      &schema.Schema{Type: schema.TypeBool},
+			want:        "false",
+			wantOK:      true,
+		},
+		{
+			name:        "bare bool",
+			description: "Default is true.",
+			schema:      &schema.Schema{Type: schema.TypeBool},
+			want:        "true",
+			wantOK:      true,
+		},
+		{
+			name:        "string",
+			description: "Storage type. Defaults to `gp2`.",
+			schema:      &schema.Schema{Type: schema.TypeString},
+			want:        `"gp2"`,
+			wantOK:      true,
+		},
+		{
+			name:        "quoted string",
+			description: "Storage type. Defaults to `\"gp2\"`.",
+			schema:      &schema.Schema{Type: schema.TypeString},
+			want:        `"gp2"`,
+			wantOK:      true,
+		},
+		{
+			name:        "not a literal",
+			description: "Defaults to the provider region.",
+			schema:      &schema.Schema{Type: schema.TypeString},
+		},
+		{
+			name:        "not a number",
+			description: "Defaults to `unlimited`.",
+			schema:      &schema.Schema{Type: schema.TypeInt},
+		},
+		{
+			name:        "not a literal type",
+			description: "Defaults to `[]`.",
+			schema:      &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseDefault(tt.description, tt.schema)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ParseDefault() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCheckDefault(t *testing.T) {
+	tests := []struct {
+		name       string
+		schema     *schema.Schema
+		docDefault string
+		want       string
+		wantDiag   bool
+	}{
+		{
+			name:   "no docs default",
+			schema: &schema.Schema{Type: schema.TypeInt, Default: 30},
+		},
+		{
+			name:       "docs default only",
+			schema:     &schema.Schema{Type: schema.TypeInt},
+			docDefault: "30",
+			want:       "30",
+		},
+		{
+			name:       "agreeing defaults",
+			schema:     &schema.Schema{Type: schema.TypeString, Default: "gp2"},
+			docDefault: `"gp2"`,
+		},
+		{
+			name:       "disagreeing defaults",
+			schema:     &schema.Schema{Type: schema.TypeInt, Default: 30},
+			docDefault: "7",
+			wantDiag:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testResource(t)
+			entry := IOEntry{Schema: tt.schema, Resource: r, Name: "value"}
+
+			if got := r.CheckDefault(entry, tt.docDefault); got != tt.want {
+				t.Errorf("CheckDefault() = %q, want %q", got, tt.want)
+			}
+
+			mismatched := false
+			for _, d := range r.Diagnostics() {
+				mismatched = mismatched || d.Kind == DiagnosticMismatch
+			}
+			if mismatched != tt.wantDiag {
+				t.Errorf("CheckDefault() reported a mismatch: %v, want %v", mismatched, tt.wantDiag)
+			}
+		})
+	}
+}
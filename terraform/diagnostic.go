@@ -0,0 +1,129 @@
+package terraform
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Kinds of Diagnostic.
+const (
+	// DiagnosticMissingDocs is a resource without docs, generated from its
+	// schema alone.
+	DiagnosticMissingDocs = "missing_docs"
+	// DiagnosticUnparsedEntry is a list item of the Argument or Attributes
+	// Reference that isn't in the "`name` - (Optional) description" format.
+	DiagnosticUnparsedEntry = "unparsed_entry"
+	// DiagnosticUndocumented is an argument of the schema the docs don't
+	// document.
+	DiagnosticUndocumented = "undocumented"
+	// DiagnosticNotInSchema is a documented entry the schema doesn't have.
+	DiagnosticNotInSchema = "not_in_schema"
+	// DiagnosticUnmatchedSubsection is a heading within the Argument or
+	// Attributes Reference that doesn't name a nested block.
+	DiagnosticUnmatchedSubsection = "unmatched_subsection"
+	// DiagnosticMismatch is a validation or default the docs describe that
+	// the schema disagrees with.
+	DiagnosticMismatch = "mismatch"
+	// DiagnosticExample is an example that couldn't be rewritten into a
+	// call of the module.
+	DiagnosticExample = "example"
+)
+
+// Diagnostic is a problem found while parsing a resource's docs, and where
+// in them it was found.
+type Diagnostic struct {
+	Kind    string `json:"kind" yaml:"kind"`
+	DocPath string `json:"doc_path" yaml:"doc_path"`
+	// Line is the line of the docs the problem is on, or 0 if it isn't on
+	// any, e.g. for undocumented arguments.
+	Line int `json:"line,omitempty" yaml:"line,omitempty"`
+	// Text is the raw markdown the problem was found in, if any.
+	Text string `json:"text,omitempty" yaml:"text,omitempty"`
+	// Path is the path of the entry the problem is with, if any, e.g.
+	// replica.region.
+	Path   string `json:"path,omitempty" yaml:"path,omitempty"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+func (d Diagnostic) String() string {
+	if d.DocPath == "" {
+		return d.Reason
+	} else if d.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", d.DocPath, d.Line, d.Reason)
+	}
+	return fmt.Sprintf("%s: %s", d.DocPath, d.Reason)
+}
+
+// Diagnose logs a problem found while parsing the resource's docs, and
+// keeps it for Diagnostics.
+func (r *TFResource) Diagnose(d Diagnostic) {
+	if d.DocPath == "" {
+		d.DocPath = r.DocPath
+	}
+
+	log.Printf("[WARN] %s", d)
+	r.diagnostics = append(r.diagnostics, d)
+}
+
+// diagnoseEntry reports a problem with a documented entry, at its place in
+// the docs.
+func (r *TFResource) diagnoseEntry(kind string, entry IOEntry, format string, v ...interface{}) {
+	r.Diagnose(Diagnostic{Kind: kind, Line: entry.DocLine, Text: entry.DocText, Path: entry.Path(), Reason: fmt.Sprintf(format, v...)})
+}
+
+func (r TFResource) Diagnostics() []Diagnostic {
+	return r.diagnostics
+}
+
+// StrictDiagnostics are the diagnostics that fail generate --strict: missing
+// docs, the docs' entries that couldn't be parsed, and the schema's
+// arguments they don't document.
+func (r TFResource) StrictDiagnostics() []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, d := range r.diagnostics {
+		switch d.Kind {
+		case DiagnosticMissingDocs, DiagnosticUnparsedEntry, DiagnosticUndocumented:
+			diagnostics = append(diagnostics, d)
+		}
+	}
+	return diagnostics
+}
+
+// DiagnoseUndocumented reports the schema's arguments the docs don't have an
+// entry for, in either section (e.g. tags_all is usually only documented as
+// an attribute), including the fields of documented nested blocks.
+func (r *TFResource) DiagnoseUndocumented() {
+	if r.Resource == nil {
+		return
+	}
+
+	r.diagnoseUndocumented(r.Schema, nil, r.documentedPaths())
+}
+
+// diagnoseUndocumented reports the undocumented arguments of fields, not
+// looking into the blocks of those that are undocumented themselves.
+func (r *TFResource) diagnoseUndocumented(fields map[string]*schema.Schema, parent []string, documented map[string]bool) {
+	for _, name := range sortedFieldNames(fields) {
+		s := fields[name]
+		if !s.Required && !s.Optional {
+			continue
+		}
+
+		path := strings.Join(append(append([]string{}, parent...), name), ".")
+		if !documented[path] {
+			r.Diagnose(Diagnostic{
+				Kind:   DiagnosticUndocumented,
+				Path:   path,
+				Reason: fmt.Sprintf("argument %s of %s is in the schema, but not documented.", path, r.Type),
+			})
+			continue
+		}
+
+		if block, ok := s.Elem.(*schema.Resource); ok {
+			r.diagnoseUndocumented(block.Schema, append(append([]string{}, parent...), name), documented)
+		}
+	}
+}
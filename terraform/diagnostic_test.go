@@ -0,0 +1,56 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiagnoseUndocumented(t *testing.T) {
+	tests := []struct {
+		name       string
+		documented []string
+		want       []string
+	}{
+		{
+			name:       "everything documented",
+			documented: []string{"name", "description", "rotation_rules", "rotation_rules.automatically_after_days", "arn"},
+		},
+		{
+			name:       "only attributes are undocumented",
+			documented: []string{"name", "description", "rotation_rules", "rotation_rules.automatically_after_days"},
+		},
+		{
+			name:       "top level arguments",
+			documented: []string{"name", "rotation_rules", "rotation_rules.automatically_after_days"},
+			want:       []string{"argument description of aws_secretsmanager_secret is in the schema, but not documented."},
+		},
+		{
+			name:       "fields of a documented nested block",
+			documented: []string{"name", "description", "rotation_rules"},
+			want:       []string{"argument rotation_rules.automatically_after_days of aws_secretsmanager_secret is in the schema, but not documented."},
+		},
+		{
+			name:       "undocumented nested block",
+			documented: []string{"name", "description"},
+			want:       []string{"argument rotation_rules of aws_secretsmanager_secret is in the schema, but not documented."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testDocumentedResource(t, tt.documented...)
+			r.DiagnoseUndocumented()
+
+			var got []string
+			for _, d := range r.Diagnostics() {
+				if d.Kind != DiagnosticUndocumented {
+					t.Errorf("DiagnoseUndocumented() reported a %s diagnostic", d.Kind)
+				}
+				got = append(got, d.Reason)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiagnoseUndocumented() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
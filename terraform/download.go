@@ -0,0 +1,146 @@
+package terraform
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DownloadOptions configures how provider docs are downloaded.
+type DownloadOptions struct {
+	// MirrorURL replaces the provider's RepositoryBase, e.g. an Artifactory
+	// remote or GitHub Enterprise organization mirroring github.com/hashicorp.
+	MirrorURL string
+	// Token is sent as a bearer token with every request.
+	Token string
+	// CABundle is a PEM file of certificate authorities to trust, in addition
+	// to the system's.
+	CABundle string
+	Timeout  time.Duration
+	// Retries is the number of times a failed download is retried, waiting
+	// Backoff before the first retry and doubling it each time after.
+	Retries int
+	Backoff time.Duration
+}
+
+func DefaultDownloadOptions() DownloadOptions {
+	return DownloadOptions{
+		Timeout: 10 * time.Minute,
+		Retries: 3,
+		Backoff: 2 * time.Second,
+	}
+}
+
+// httpClient returns a client honoring the options, and the HTTP(S)_PROXY
+// and NO_PROXY environment variables.
+func (o DownloadOptions) httpClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if o.CABundle != "" {
+		pem, err := os.ReadFile(o.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle %s: %w", o.CABundle, err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", o.CABundle)
+		}
+
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{Transport: transport, Timeout: o.Timeout}, nil
+}
+
+// download fetches uri into out, retrying with backoff on connection errors,
+// on responses a retry could fix (429 and 5xx), and on transfers cut short.
+// out is truncated before each attempt.
+func (o DownloadOptions) download(uri string, out *os.File) error {
+	client, err := o.httpClient()
+	if err != nil {
+		return err
+	}
+
+	backoff := o.Backoff
+	for attempt := 0; ; attempt++ {
+		var response *http.Response
+
+		response, err = o.do(client, uri)
+		if err == nil {
+			if err = copyBody(out, response); err == nil {
+				return nil
+			}
+			// the request completed, but the transfer didn't
+			response = nil
+		}
+
+		if attempt >= o.Retries || !retryable(response) {
+			return err
+		}
+
+		log.Printf("[WARN] %s; retrying in %s", err, backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// copyBody writes the body of the response over out, closing the body.
+func copyBody(out *os.File, response *http.Response) error {
+	defer response.Body.Close()
+
+	if err := out.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate %s: %w", out.Name(), err)
+	}
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind %s: %w", out.Name(), err)
+	}
+
+	if _, err := io.Copy(out, response.Body); err != nil {
+		return fmt.Errorf("failed to write http response to file: %w", err)
+	}
+
+	return nil
+}
+
+func (o DownloadOptions) do(client *http.Client, uri string) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", uri, err)
+	}
+
+	if o.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+o.Token)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch provider from %s: %w", uri, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return response, fmt.Errorf("received status code %d while fetching docs from %s", response.StatusCode, uri)
+	}
+
+	return response, nil
+}
+
+// retryable reports whether a failed request is worth retrying; a nil
+// response means the request never completed.
+func retryable(response *http.Response) bool {
+	if response == nil {
+		return true
+	}
+
+	return response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= http.StatusInternalServerError
+}
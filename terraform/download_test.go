@@ -0,0 +1,229 @@
+package terraform
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testDownloadOptions retry quickly, so tests of retries stay fast.
+func testDownloadOptions() DownloadOptions {
+	return DownloadOptions{Timeout: 5 * time.Second, Retries: 2, Backoff: time.Millisecond}
+}
+
+func downloadString(t *testing.T, o DownloadOptions, uri string) (string, error) {
+	t.Helper()
+
+	out, err := os.CreateTemp(t.TempDir(), "download-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+
+	if err := o.download(uri, out); err != nil {
+		return "", err
+	}
+
+	b, err := os.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b), nil
+}
+
+func TestDownloadRetries(t *testing.T) {
+	tests := []struct {
+		name string
+		// statuses are the responses to each attempt; 0 truncates the body
+		statuses     []int
+		wantErr      bool
+		wantAttempts int32
+	}{
+		{name: "ok", statuses: []int{200}, wantAttempts: 1},
+		{name: "too many requests", statuses: []int{429, 200}, wantAttempts: 2},
+		{name: "server errors", statuses: []int{500, 503, 200}, wantAttempts: 3},
+		{name: "truncated transfer", statuses: []int{0, 200}, wantAttempts: 2},
+		{name: "not found", statuses: []int{404, 200}, wantErr: true, wantAttempts: 1},
+		{name: "forbidden", statuses: []int{403, 200}, wantErr: true, wantAttempts: 1},
+		{name: "out of retries", statuses: []int{503, 503, 503, 200}, wantErr: true, wantAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[atomic.AddInt32(&attempts, 1)-1]
+				if status == 0 {
+					// promise more than is sent, cutting the transfer short
+					w.Header().Set("Content-Length", "100")
+					w.Write([]byte("partial"))
+					return
+				}
+				w.WriteHeader(status)
+				w.Write([]byte("archive"))
+			}))
+			defer server.Close()
+
+			got, err := downloadString(t, testDownloadOptions(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("download() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != "archive" {
+				t.Errorf("download() wrote %q, want %q", got, "archive")
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("download() made %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestDownloadToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{name: "no token", token: "", want: ""},
+		{name: "bearer token", token: "s3cret", want: "Bearer s3cret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+			}))
+			defer server.Close()
+
+			o := testDownloadOptions()
+			o.Token = tt.token
+			if _, err := downloadString(t, o, server.URL); err != nil {
+				t.Fatalf("download() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Authorization = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	o := testDownloadOptions()
+	o.Timeout = 50 * time.Millisecond
+	o.Retries = 0
+
+	if _, err := downloadString(t, o, server.URL); err == nil {
+		t.Fatal("download() succeeded, want a timeout")
+	}
+}
+
+func TestDownloadCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("archive"))
+	}))
+	defer server.Close()
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(bundle, certificate, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	o := testDownloadOptions()
+	o.Retries = 0
+	if _, err := downloadString(t, o, server.URL); err == nil {
+		t.Error("download() trusted an unknown certificate authority")
+	}
+
+	o.CABundle = bundle
+	if _, err := downloadString(t, o, server.URL); err != nil {
+		t.Errorf("download() error = %v with the CA bundle", err)
+	}
+}
+
+func TestArchiveURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		mirror string
+		want   string
+	}{
+		{
+			name: "github",
+			want: "https://github.com/hashicorp/terraform-provider-aws/archive/refs/tags/v4.29.0.zip",
+		},
+		{
+			name:   "mirror",
+			mirror: "https://artifactory.example.com/artifactory/github/hashicorp",
+			want:   "https://artifactory.example.com/artifactory/github/hashicorp/terraform-provider-aws/archive/refs/tags/v4.29.0.zip",
+		},
+		{
+			name:   "mirror with trailing slash",
+			mirror: "https://github.example.com/hashicorp/",
+			want:   "https://github.example.com/hashicorp/terraform-provider-aws/archive/refs/tags/v4.29.0.zip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testProvider(t)
+			p.SetDownloadOptions(DownloadOptions{MirrorURL: tt.mirror})
+
+			got, err := p.archiveURL()
+			if err != nil {
+				t.Fatalf("archiveURL() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("archiveURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadDocsFromMirror(t *testing.T) {
+	archive, err := os.ReadFile(writeZip(t, []zipEntry{
+		{"terraform-provider-aws-4.29.0/website/docs/r/secretsmanager_secret.html.markdown", "docs"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/mirror/terraform-provider-aws/archive/refs/tags/v4.29.0.zip") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	p := testProvider(t)
+	o := testDownloadOptions()
+	o.MirrorURL = server.URL + "/mirror"
+	p.SetDownloadOptions(o)
+
+	if err := p.DownloadDocs(); err != nil {
+		t.Fatalf("DownloadDocs() error = %v", err)
+	}
+	if !p.DocCacheComplete() {
+		t.Error("DownloadDocs() didn't mark the doc cache complete")
+	}
+	if _, err := p.ResourceDocPath("aws_secretsmanager_secret"); err != nil {
+		t.Errorf("ResourceDocPath() error = %v", err)
+	}
+}
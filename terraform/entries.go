@@ -0,0 +1,113 @@
+package terraform
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Documented reports whether the entry was parsed from the docs, rather than
+// only found in the schema.
+func (e IOEntry) Documented() bool {
+	return e.DocLine > 0
+}
+
+// AppendSchemaEntries makes the schema the source of truth for the top level
+// arguments and attributes: the docs' arguments are completed with the
+// schema's Required and Optional fields, and the attributes are replaced by
+// its outputs. Fields the docs only describe as attributes (e.g. tags_all,
+// which is Optional and Computed, but only ever set by the provider) stay
+// attributes. Entries without a description from the docs are then
+// described by the schema, or a placeholder.
+func (r *TFResource) AppendSchemaEntries() {
+	if r.Resource == nil {
+		return
+	}
+	r.docPaths = r.documentedPaths()
+
+	documentedAttributes := make(map[string]bool)
+	for _, attribute := range r.Attributes() {
+		documentedAttributes[attribute.Name] = IOEntry(attribute).Documented()
+	}
+
+	for _, name := range sortedFieldNames(r.Schema) {
+		s := r.Schema[name]
+		if _, documented := r.arguments.keys[name]; documented || documentedAttributes[name] || (!s.Required && !s.Optional) {
+			continue
+		}
+
+		entry := IOEntry{
+			Schema:     s,
+			Resource:   r,
+			Name:       name,
+			Optional:   s.Optional,
+			Deprecated: s.Deprecated != "",
+		}
+		entry.Constraint = r.CheckConstraint(entry, nil)
+		r.AppendArgument(InputEntry(entry))
+	}
+
+	r.attributes = r.outputs()
+
+	for i, argument := range r.arguments.Arguments {
+		r.arguments.Arguments[i].Description = IOEntry(argument).describe("argument")
+	}
+	for i, attribute := range r.attributes.Attributes {
+		r.attributes.Attributes[i].Description = IOEntry(attribute).describe("attribute")
+	}
+	for i, entry := range r.nested.Entries {
+		r.nested.Entries[i].Description = entry.describe("field")
+	}
+}
+
+// describe is the entry's description from the docs, else from the schema,
+// else a placeholder naming what kind of entry it is.
+func (e IOEntry) describe(kind string) string {
+	if e.Description != "" {
+		return e.Description
+	} else if e.Schema != nil && e.Schema.Description != "" {
+		return e.Schema.Description
+	}
+
+	return fmt.Sprintf("The %s %s of %s (undocumented).", e.Path(), kind, e.Resource.Type)
+}
+
+// outputs are the attributes of the schema's Computed fields, including
+// arguments the provider fills in when they're unset (e.g. name, when
+// name_prefix is used), and with EchoOutputs, every other argument too.
+// Documented attributes come first, in the order of the docs.
+func (r *TFResource) outputs() *AttributeList {
+	outputs := NewAttributeList()
+
+	for _, attribute := range r.Attributes() {
+		if attribute.Name == "id" || r.isOutput(attribute.Schema) {
+			outputs.Append(attribute)
+		}
+	}
+
+	// id is implicit, so never in the schema
+	outputs.Append(OutputEntry{Resource: r, Name: "id"})
+
+	for _, argument := range r.Arguments() {
+		if r.isOutput(argument.Schema) {
+			outputs.Append(OutputEntry(argument))
+		}
+	}
+
+	for _, name := range sortedFieldNames(r.Schema) {
+		if s := r.Schema[name]; r.isOutput(s) {
+			outputs.Append(OutputEntry{
+				Schema:     s,
+				Resource:   r,
+				Name:       name,
+				Deprecated: s.Deprecated != "",
+			})
+		}
+	}
+
+	return outputs
+}
+
+func (r TFResource) isOutput(s *schema.Schema) bool {
+	return s != nil && (s.Computed || (r.EchoOutputs && (s.Required || s.Optional)))
+}
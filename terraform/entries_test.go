@@ -0,0 +1,158 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func argumentNames(r *TFResource) []string {
+	var names []string
+	for _, argument := range r.Arguments() {
+		names = append(names, argument.Name)
+	}
+	return names
+}
+
+func attributeNames(r *TFResource) []string {
+	var names []string
+	for _, attribute := range r.Attributes() {
+		names = append(names, attribute.Name)
+	}
+	return names
+}
+
+func TestAppendSchemaEntries(t *testing.T) {
+	tests := []struct {
+		name           string
+		documented     []string
+		tagsAllDocs    bool
+		echoOutputs    bool
+		wantArguments  []string
+		wantAttributes []string
+	}{
+		{
+			name:           "no docs",
+			wantArguments:  []string{"description", "name", "rotation_rules", "tags_all"},
+			wantAttributes: []string{"id", "tags_all", "arn"},
+		},
+		{
+			name:           "documented arguments keep their order",
+			documented:     []string{"name", "description"},
+			wantArguments:  []string{"name", "description", "rotation_rules", "tags_all"},
+			wantAttributes: []string{"id", "tags_all", "arn"},
+		},
+		{
+			name:           "documented attributes come first",
+			documented:     []string{"arn"},
+			wantArguments:  []string{"description", "name", "rotation_rules", "tags_all"},
+			wantAttributes: []string{"arn", "id", "tags_all"},
+		},
+		{
+			name:           "fields only documented as attributes",
+			tagsAllDocs:    true,
+			wantArguments:  []string{"description", "name", "rotation_rules"},
+			wantAttributes: []string{"tags_all", "id", "arn"},
+		},
+		{
+			name:           "echoed outputs",
+			documented:     []string{"name"},
+			echoOutputs:    true,
+			wantArguments:  []string{"name", "description", "rotation_rules", "tags_all"},
+			wantAttributes: []string{"id", "name", "description", "rotation_rules", "tags_all", "arn"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testDocumentedResource(t, tt.documented...)
+			r.EchoOutputs = tt.echoOutputs
+			r.Schema["tags_all"] = &schema.Schema{Type: schema.TypeMap, Optional: true, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}}
+			if tt.tagsAllDocs {
+				r.AppendAttribute(OutputEntry{Schema: r.Schema["tags_all"], Resource: r, Name: "tags_all", Description: "Map of tags.", DocLine: 1})
+			}
+
+			r.AppendSchemaEntries()
+
+			if got := argumentNames(r); !reflect.DeepEqual(got, tt.wantArguments) {
+				t.Errorf("arguments = %v, want %v", got, tt.wantArguments)
+			}
+			if got := attributeNames(r); !reflect.DeepEqual(got, tt.wantAttributes) {
+				t.Errorf("attributes = %v, want %v", got, tt.wantAttributes)
+			}
+
+			for _, argument := range r.Arguments() {
+				if argument.Description == "" {
+					t.Errorf("argument %s has no description", argument.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestVariables(t *testing.T) {
+	r := testResource(t)
+	r.Schema["recovery_window_in_days"] = &schema.Schema{Type: schema.TypeInt, Optional: true, Default: 30}
+	r.AppendArgument(InputEntry{Schema: r.Schema["recovery_window_in_days"], Resource: r, Name: "recovery_window_in_days"})
+	// constraint values Terraform would otherwise interpolate
+	r.Schema["policy_variable"] = &schema.Schema{Type: schema.TypeString, Optional: true}
+	r.AppendArgument(InputEntry{
+		Schema:     r.Schema["policy_variable"],
+		Resource:   r,
+		Name:       "policy_variable",
+		Constraint: &Constraint{Values: []string{"${aws:username}", "%{ if true }"}},
+	})
+	dir := t.TempDir()
+
+	input := NewTFInput(r)
+	f, err := os.Create(filepath.Join(dir, "variables.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := input.Create(f); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	b, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parseHCL(t, string(b))
+
+	// each variable's block, by name
+	blocks := make(map[string]string)
+	for _, block := range strings.Split(string(b), "\nvariable ")[1:] {
+		name := strings.Trim(strings.Fields(block)[0], `"`)
+		blocks[name] = squash(block)
+	}
+
+	tests := []struct {
+		variable string
+		want     string
+	}{
+		{variable: "secret_name", want: ""},
+		{variable: "secret_description", want: "default = null"},
+		{variable: "secret_rotation_rules", want: "default = null"},
+		{variable: "secret_recovery_window_in_days", want: "default = 30"},
+		{variable: "secret_policy_variable", want: `error_message = "The policy_variable must be one of $${aws:username}, %%{ if true }."`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.variable, func(t *testing.T) {
+			block, ok := blocks[tt.variable]
+			if !ok {
+				t.Fatalf("no variable %s in:\n%s", tt.variable, b)
+			}
+			if tt.want == "" && strings.Contains(block, "default") {
+				t.Errorf("required variable %s has a default: %s", tt.variable, block)
+			} else if !strings.Contains(block, tt.want) {
+				t.Errorf("variable %s = %s, want %q", tt.variable, block, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+package terraform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/infracasts/terrawrap-cli/tpl"
+)
+
+// Example is an HCL code block from the docs' Example Usage section.
+type Example struct {
+	// Title is the heading the example is under, e.g. "Rotation
+	// Configuration"; empty if it's directly under Example Usage.
+	Title string `json:"title" yaml:"title"`
+	HCL   string `json:"hcl" yaml:"hcl"`
+	// Line is the line of the docs the code block starts on.
+	Line int `json:"line" yaml:"line"`
+}
+
+// AppendExample records an Example Usage code block.
+func (r *TFResource) AppendExample(example Example) {
+	r.examples = append(r.examples, example)
+}
+
+func (r TFResource) Examples() []Example {
+	return r.examples
+}
+
+// exampleMetaArguments are passed through to the module call unchanged, as
+// modules support them too.
+var exampleMetaArguments = map[string]bool{"count": true, "for_each": true, "depends_on": true}
+
+// exampleRewriter rewrites an example's resource blocks of the resource's
+// type into calls of the generated module.
+type exampleRewriter struct {
+	resource *TFResource
+	example  Example
+	source   []byte
+	// replacements of references to the rewritten resources, by start byte
+	replacements map[int]exampleReplacement
+	// declared are the addresses the example defines, e.g.
+	// aws_iam_role.example, data.aws_region.current, var.name
+	declared map[string]bool
+	// placeholders are the variables standing in for references to what the
+	// example doesn't define, by name, with their descriptions
+	placeholders map[string]string
+	// unresolved is the first reference that can't be stood in for
+	unresolved string
+}
+
+type exampleReplacement struct {
+	end  int
+	text string
+}
+
+// ModuleExample rewrites an example into a call of the generated module,
+// mapping the resource's arguments to the module's variables, and
+// references to its attributes to the module's outputs. Other blocks in the
+// example are kept as they are.
+func (r *TFResource) ModuleExample(example Example, moduleSource string) (string, error) {
+	source := []byte(example.HCL)
+
+	file, diags := hclsyntax.ParseConfig(source, "example.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("failed to parse example %q: %s", example.Title, diags.Error())
+	}
+	body := file.Body.(*hclsyntax.Body)
+
+	w := &exampleRewriter{
+		resource:     r,
+		example:      example,
+		source:       source,
+		replacements: make(map[int]exampleReplacement),
+		declared:     exampleDeclarations(body),
+		placeholders: make(map[string]string),
+	}
+	w.collectReferences(body)
+	if w.unresolved != "" {
+		return "", fmt.Errorf("example %q refers to %s, which it doesn't define", example.Title, w.unresolved)
+	}
+
+	var out strings.Builder
+	for _, block := range body.Blocks {
+		if block.Type == "resource" && len(block.Labels) == 2 && block.Labels[0] == r.Type {
+			out.WriteString(w.moduleCall(block, moduleSource))
+		} else {
+			out.WriteString(w.text(block.Range()))
+		}
+		out.WriteString("\n\n")
+	}
+
+	names := make([]string, 0, len(w.placeholders))
+	for name := range w.placeholders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&out, "variable %q {\ndescription = %q\n}\n\n", name, w.placeholders[name])
+	}
+
+	return string(hclwrite.Format([]byte(out.String()))), nil
+}
+
+// exampleDeclarations are the addresses the blocks of an example define.
+func exampleDeclarations(body *hclsyntax.Body) map[string]bool {
+	declared := make(map[string]bool)
+
+	for _, block := range body.Blocks {
+		switch {
+		case block.Type == "resource" && len(block.Labels) == 2:
+			declared[block.Labels[0]+"."+block.Labels[1]] = true
+		case block.Type == "data" && len(block.Labels) == 2:
+			declared["data."+block.Labels[0]+"."+block.Labels[1]] = true
+		case block.Type == "variable" && len(block.Labels) == 1:
+			declared["var."+block.Labels[0]] = true
+		case block.Type == "module" && len(block.Labels) == 1:
+			declared["module."+block.Labels[0]] = true
+		case block.Type == "locals":
+			for name := range block.Body.Attributes {
+				declared["local."+name] = true
+			}
+		}
+	}
+
+	return declared
+}
+
+// exampleBuiltins are the roots of references Terraform itself defines.
+var exampleBuiltins = map[string]bool{"count": true, "each": true, "self": true, "path": true, "terraform": true}
+
+// placeholder stands in a variable for a reference to something the example
+// doesn't define, e.g. var.aws_lambda_function_example_arn for
+// aws_lambda_function.example.arn. Undefined variables are simply declared;
+// locals and modules, and references to a whole resource (as in depends_on),
+// can't be stood in for.
+func (w *exampleRewriter) placeholder(traversal hcl.Traversal) {
+	root := traversal.RootName()
+	if exampleBuiltins[root] {
+		return
+	}
+
+	// the address is the root, and the labels of what it refers to
+	names := traversalNames(traversal)
+	labels := 2
+	if root == "data" {
+		labels = 3
+	}
+	if len(names) < labels {
+		return
+	}
+
+	address := strings.Join(names[:labels], ".")
+	if w.declared[address] {
+		return
+	}
+
+	switch {
+	case root == "var":
+		w.placeholders[names[1]] = fmt.Sprintf("The example's %s, which it doesn't declare.", names[1])
+		return
+	case root == "local" || root == "module" || len(names) == labels:
+		if w.unresolved == "" {
+			w.unresolved = address
+		}
+		return
+	}
+
+	// the attribute referred to is part of what's stood in for
+	name := strings.Join(names[:labels+1], "_")
+	reference := strings.Join(names[:labels+1], ".")
+	w.placeholders[name] = fmt.Sprintf("Stands in for %s, which the example doesn't define.", reference)
+
+	rng := traversal[labels].SourceRange()
+	w.replacements[traversal[0].SourceRange().Start.Byte] = exampleReplacement{end: rng.End.Byte, text: "var." + name}
+	w.resource.Diagnose(Diagnostic{
+		Kind:   DiagnosticExample,
+		Line:   w.example.Line,
+		Reason: fmt.Sprintf("example refers to %s, which it doesn't define, so var.%s stands in for it.", reference, name),
+	})
+}
+
+// collectReferences finds every reference to a resource being rewritten,
+// e.g. aws_secretsmanager_secret.example.arn, and what it becomes, e.g.
+// module.example.aws_secretsmanager_secret_default_arn.
+func (w *exampleRewriter) collectReferences(body *hclsyntax.Body) {
+	for _, attr := range body.Attributes {
+		for _, traversal := range attr.Expr.Variables() {
+			if traversal.RootName() != w.resource.Type || len(traversal) < 2 {
+				w.placeholder(traversal)
+				continue
+			}
+
+			label, ok := traversal[1].(hcl.TraverseAttr)
+			if !ok {
+				continue
+			}
+
+			replacement := exampleReplacement{
+				end:  traversal[1].SourceRange().End.Byte,
+				text: "module." + label.Name,
+			}
+
+			// Indexes of counted resources carry over, e.g. [0] in
+			// aws_secretsmanager_secret.example[0].arn
+			var indexes strings.Builder
+			i := 2
+			for ; i < len(traversal); i++ {
+				if _, ok := traversal[i].(hcl.TraverseIndex); !ok {
+					break
+				}
+				rng := traversal[i].SourceRange()
+				indexes.Write(w.source[rng.Start.Byte:rng.End.Byte])
+			}
+
+			if i < len(traversal) {
+				if attribute, ok := traversal[i].(hcl.TraverseAttr); ok {
+					replacement.end = traversal[i].SourceRange().End.Byte
+					replacement.text += indexes.String() + "." + OutputEntry{Resource: w.resource, Name: attribute.Name}.PrefixedName()
+				}
+			}
+
+			w.replacements[traversal[0].SourceRange().Start.Byte] = replacement
+		}
+	}
+
+	for _, block := range body.Blocks {
+		w.collectReferences(block.Body)
+	}
+}
+
+// traversalNames are the names a traversal starts with, up to its first
+// index, e.g. aws_lambda_function, example, arn.
+func traversalNames(traversal hcl.Traversal) []string {
+	var names []string
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		default:
+			return names
+		}
+	}
+
+	return names
+}
+
+// text is the source of rng, with references rewritten.
+func (w *exampleRewriter) text(rng hcl.Range) string {
+	var out strings.Builder
+
+	for i := rng.Start.Byte; i < rng.End.Byte; i++ {
+		if replacement, ok := w.replacements[i]; ok && replacement.end <= rng.End.Byte {
+			out.WriteString(replacement.text)
+			i = replacement.end - 1
+			continue
+		}
+		out.WriteByte(w.source[i])
+	}
+
+	return out.String()
+}
+
+func (w *exampleRewriter) moduleCall(block *hclsyntax.Block, moduleSource string) string {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "module %q {\n", block.Labels[1])
+	fmt.Fprintf(&out, "source = %q\n", moduleSource)
+
+	for _, attr := range sortedAttributes(block.Body) {
+		switch {
+		case exampleMetaArguments[attr.Name]:
+			fmt.Fprintf(&out, "%s = %s\n", attr.Name, w.text(attr.Expr.Range()))
+		case attr.Name == "provider":
+			fmt.Fprintf(&out, "providers = {\n%s = %s\n}\n", providerLocalName(w.resource.Type), w.text(attr.Expr.Range()))
+		default:
+			if variable, ok := w.variable(attr.Name); ok {
+				fmt.Fprintf(&out, "%s = %s\n", variable, w.text(attr.Expr.Range()))
+			}
+		}
+	}
+
+	// Nested blocks become lists of objects, e.g. rotation_rules = [{...}]
+	var blockTypes []string
+	blocks := make(map[string][]*hclsyntax.Block)
+	for _, nested := range block.Body.Blocks {
+		if nested.Type == "lifecycle" {
+			continue
+		}
+		if _, ok := blocks[nested.Type]; !ok {
+			blockTypes = append(blockTypes, nested.Type)
+		}
+		blocks[nested.Type] = append(blocks[nested.Type], nested)
+	}
+
+	for _, blockType := range blockTypes {
+		if variable, ok := w.variable(blockType); ok {
+			fmt.Fprintf(&out, "%s = %s\n", variable, w.objectList(blocks[blockType]))
+		}
+	}
+
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// variable is the name of the module variable for an argument.
+func (w *exampleRewriter) variable(name string) (string, bool) {
+	for _, argument := range w.resource.Arguments() {
+		if argument.Name == name {
+			return argument.PrefixedName(), true
+		}
+	}
+
+	w.resource.Diagnose(Diagnostic{
+		Kind:   DiagnosticExample,
+		Line:   w.example.Line,
+		Reason: fmt.Sprintf("example argument %s of %s has no module variable, so it was omitted from the example.", name, w.resource.Type),
+	})
+	return "", false
+}
+
+func (w *exampleRewriter) objectList(blocks []*hclsyntax.Block) string {
+	var out strings.Builder
+
+	out.WriteString("[\n")
+	for _, block := range blocks {
+		out.WriteString("{\n")
+		for _, attr := range sortedAttributes(block.Body) {
+			fmt.Fprintf(&out, "%s = %s\n", attr.Name, w.text(attr.Expr.Range()))
+		}
+
+		var blockTypes []string
+		nested := make(map[string][]*hclsyntax.Block)
+		for _, child := range block.Body.Blocks {
+			if _, ok := nested[child.Type]; !ok {
+				blockTypes = append(blockTypes, child.Type)
+			}
+			nested[child.Type] = append(nested[child.Type], child)
+		}
+		for _, blockType := range blockTypes {
+			fmt.Fprintf(&out, "%s = %s\n", blockType, w.objectList(nested[blockType]))
+		}
+
+		out.WriteString("},\n")
+	}
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// sortedAttributes returns the attributes of a body in source order.
+func sortedAttributes(body *hclsyntax.Body) []*hclsyntax.Attribute {
+	attrs := make([]*hclsyntax.Attribute, 0, len(body.Attributes))
+	for _, attr := range body.Attributes {
+		attrs = append(attrs, attr)
+	}
+
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].SrcRange.Start.Byte < attrs[j].SrcRange.Start.Byte
+	})
+
+	return attrs
+}
+
+// providerLocalName is the provider a resource type belongs to, e.g. aws
+// for aws_secretsmanager_secret.
+func providerLocalName(resourceType string) string {
+	return strings.SplitN(resourceType, "_", 2)[0]
+}
+
+// ImportExample is a root configuration calling the module from
+// moduleSource, with an import block adopting an existing resource as the
+// module's. Terraform only allows import blocks in the root module, so it
+// can't be in the module itself. The required arguments and the ID to import
+// are passed through from variables.
+func (r *TFResource) ImportExample(moduleSource string) string {
+	var module, variables strings.Builder
+
+	fmt.Fprintf(&module, "module %q {\n", r.Name)
+	fmt.Fprintf(&module, "source = %q\n", moduleSource)
+	for _, argument := range r.Arguments() {
+		if !isRequired(argument) || argument.DefaultValue() != "" {
+			continue
+		}
+
+		fmt.Fprintf(&module, "%s = var.%s\n", argument.PrefixedName(), argument.PrefixedName())
+		fmt.Fprintf(&variables, "\nvariable %q {\ntype = %s\ndescription = %q\n}\n", argument.PrefixedName(), argument.ValueType(), argument.Description)
+	}
+	module.WriteString("}\n")
+
+	fmt.Fprintf(&module, "\nimport {\nto = module.%s.%s.%s\nid = var.%s\n}\n", r.Name, r.Type, r.Name, r.ImportIDVariable())
+	fmt.Fprintf(&module, "\nvariable %q {\ntype = string\ndescription = %q\n}\n", r.ImportIDVariable(), r.ImportIDDescription())
+
+	return string(hclwrite.Format([]byte(module.String() + variables.String())))
+}
+
+var exampleDirName = regexp.MustCompile(`[^a-z0-9]+`)
+
+// TFExample is a usage example of the generated module, derived from the
+// docs' Example Usage section.
+type TFExample struct {
+	*TFResource
+	// HCL is the module call, and whatever else the example needs.
+	HCL          string
+	Dir          string
+	templateName string
+	filename     string
+}
+
+// NewTFExamples rewrites each of the resource's examples into an example of
+// the module, under examples/<title>. Examples that can't be rewritten are
+// skipped.
+func NewTFExamples(resource *TFResource) []TFExample {
+	var examples []TFExample
+	dirs := make(map[string]int)
+
+	for _, example := range resource.Examples() {
+		module, err := resource.ModuleExample(example, "../..")
+		if err != nil {
+			resource.Diagnose(Diagnostic{Kind: DiagnosticExample, Line: example.Line, Text: example.HCL, Reason: err.Error()})
+			continue
+		}
+
+		dir := strings.Trim(exampleDirName.ReplaceAllString(strings.ToLower(example.Title), "_"), "_")
+		if dir == "" {
+			dir = "example"
+		}
+		if dirs[dir]++; dirs[dir] > 1 {
+			dir = fmt.Sprintf("%s_%d", dir, dirs[dir])
+		}
+
+		examples = append(examples, TFExample{
+			TFResource:   resource,
+			HCL:          module,
+			Dir:          dir,
+			templateName: "example",
+			filename:     "main.tf",
+		})
+	}
+
+	return examples
+}
+
+func (ex TFExample) TemplateName() string {
+	return ex.templateName
+}
+
+func (ex TFExample) FilePath() string {
+	return fmt.Sprintf("%s/examples/%s/%s", ex.AbsolutePath, ex.Dir, ex.filename)
+}
+
+func (ex TFExample) Template() *template.Template {
+	t := template.New(ex.TemplateName())
+	return template.Must(t.Parse(string(tpl.ExampleTemplate())))
+}
+
+func (ex TFExample) Create(file *os.File) error {
+	err := ex.Template().Execute(file, ex)
+	if err != nil {
+		return fmt.Errorf("failed to execute %s template: %w", ex.TemplateName(), err)
+	}
+	return nil
+}
+
+// createExamples writes the module's examples, if the docs had any.
+func (r *TFResource) createExamples() error {
+	for _, example := range NewTFExamples(r) {
+		if err := os.MkdirAll(filepath.Dir(example.FilePath()), 0754); err != nil {
+			return fmt.Errorf("failed to create example directory: %w", err)
+		}
+
+		// Regenerating replaces the examples, rather than repeating them
+		if err := createOrTruncateFile(example); err != nil {
+			return fmt.Errorf("failed to generate template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// createImportExample writes examples/import, replacing any earlier one.
+func (r *TFResource) createImportExample() error {
+	example := TFExample{
+		TFResource:   r,
+		HCL:          r.ImportExample("../.."),
+		Dir:          "import",
+		templateName: "example",
+		filename:     "main.tf",
+	}
+
+	if err := os.MkdirAll(filepath.Dir(example.FilePath()), 0754); err != nil {
+		return fmt.Errorf("failed to create example directory: %w", err)
+	}
+
+	if err := createOrTruncateFile(example); err != nil {
+		return fmt.Errorf("failed to generate template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,245 @@
+package terraform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testSchema is a cut down aws_secretsmanager_secret.
+func testSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":        {Type: schema.TypeString, Required: true},
+			"description": {Type: schema.TypeString, Optional: true},
+			"rotation_rules": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"automatically_after_days": {Type: schema.TypeInt, Required: true},
+					},
+				},
+			},
+			"arn": {Type: schema.TypeString, Computed: true},
+		},
+	}
+}
+
+// testDocumentedResource is a cut down aws_secretsmanager_secret whose docs
+// have entries for the paths given, in order, as either section would: top
+// level arguments and attributes, and fields of nested blocks.
+func testDocumentedResource(t *testing.T, paths ...string) *TFResource {
+	t.Helper()
+
+	r := NewTFResource()
+	r.Module = &Module{}
+	r.Type = "aws_secretsmanager_secret"
+	r.Name = "default"
+	r.VarPrefix = "secret"
+	r.AttrPrefix = "secret"
+	r.DocPath = "secretsmanager_secret.html.markdown"
+	r.Resource = testSchema()
+
+	for i, path := range paths {
+		names := strings.Split(path, ".")
+		entry := IOEntry{
+			Resource: &r,
+			Parent:   names[:len(names)-1],
+			Name:     names[len(names)-1],
+			DocLine:  i + 1,
+		}
+		entry.Schema, _ = r.NestedSchema(names...)
+
+		switch {
+		case len(entry.Parent) > 0:
+			r.AppendNestedEntry(entry)
+		case entry.Schema != nil && (entry.Schema.Required || entry.Schema.Optional):
+			r.AppendArgument(InputEntry(entry))
+		default:
+			r.AppendAttribute(OutputEntry(entry))
+		}
+	}
+
+	return &r
+}
+
+// testResource is testDocumentedResource without docs, its arguments
+// appended from the schema.
+func testResource(t *testing.T) *TFResource {
+	t.Helper()
+
+	r := testDocumentedResource(t)
+	r.AppendSchemaEntries()
+
+	return r
+}
+
+func parseHCL(t *testing.T, src string) {
+	t.Helper()
+
+	if _, diags := hclsyntax.ParseConfig([]byte(src), "main.tf", hcl.InitialPos); diags.HasErrors() {
+		t.Fatalf("invalid HCL: %s\n%s", diags.Error(), src)
+	}
+}
+
+// squash collapses runs of whitespace, so hclwrite's alignment doesn't
+// matter to comparisons.
+func squash(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func TestImportExample(t *testing.T) {
+	r := testResource(t)
+	r.Import = ImportDocs{Example: "arn:aws:secretsmanager:us-east-1:123456789012:secret:example-123456"}
+
+	got := r.ImportExample("../..")
+	parseHCL(t, got)
+
+	for _, want := range []string{
+		`module "default" {`,
+		`source = "../.."`,
+		`secret_name = var.secret_name`,
+		`variable "secret_name" {`,
+		`to = module.default.aws_secretsmanager_secret.default`,
+		`id = var.secret_import_id`,
+		`variable "secret_import_id" {`,
+		`e.g. arn:aws:secretsmanager:us-east-1:123456789012:secret:example-123456`,
+	} {
+		if !strings.Contains(squash(got), want) {
+			t.Errorf("ImportExample() is missing %q:\n%s", want, got)
+		}
+	}
+
+	// optional arguments are left to the module's defaults
+	if strings.Contains(got, "secret_description") {
+		t.Errorf("ImportExample() passes an optional argument:\n%s", got)
+	}
+}
+
+func TestModuleExample(t *testing.T) {
+	tests := []struct {
+		name    string
+		hcl     string
+		want    []string
+		notWant []string
+		wantErr bool
+	}{
+		{
+			name: "arguments",
+			hcl: `resource "aws_secretsmanager_secret" "example" {
+  name = "example"
+}`,
+			want: []string{`module "example" {`, `source = "../.."`, `secret_name = "example"`},
+		},
+		{
+			name: "nested blocks",
+			hcl: `resource "aws_secretsmanager_secret" "example" {
+  name = "example"
+  rotation_rules {
+    automatically_after_days = 7
+  }
+}`,
+			want: []string{`secret_rotation_rules = [ { automatically_after_days = 7 }, ]`},
+		},
+		{
+			name: "arguments without a variable",
+			hcl: `resource "aws_secretsmanager_secret" "example" {
+  name    = "example"
+  unknown = true
+}`,
+			notWant: []string{"unknown"},
+		},
+		{
+			name: "references to the resource",
+			hcl: `resource "aws_secretsmanager_secret" "example" {
+  name = "example"
+}
+
+resource "aws_secretsmanager_secret_version" "example" {
+  secret_id = aws_secretsmanager_secret.example.arn
+}`,
+			want: []string{`secret_id = module.example.secret_arn`},
+		},
+		{
+			name: "references to what the example defines",
+			hcl: `resource "aws_kms_key" "example" {}
+
+resource "aws_secretsmanager_secret" "example" {
+  name        = "example"
+  description = aws_kms_key.example.description
+}`,
+			want:    []string{`secret_description = aws_kms_key.example.description`},
+			notWant: []string{`variable "aws_kms_key_example_description"`},
+		},
+		{
+			name: "references to what the example doesn't define",
+			hcl: `resource "aws_secretsmanager_secret" "example" {
+  name        = data.aws_region.current.name
+  description = aws_lambda_function.example.arn
+}`,
+			want: []string{
+				`secret_name = var.data_aws_region_current_name`,
+				`secret_description = var.aws_lambda_function_example_arn`,
+				`variable "aws_lambda_function_example_arn" {`,
+				`variable "data_aws_region_current_name" {`,
+			},
+		},
+		{
+			name: "undeclared variables",
+			hcl: `resource "aws_secretsmanager_secret" "example" {
+  name = var.secret_name
+}`,
+			want: []string{`secret_name = var.secret_name`, `variable "secret_name" {`},
+		},
+		{
+			name: "undefined locals",
+			hcl: `resource "aws_secretsmanager_secret" "example" {
+  name = local.name
+}`,
+			wantErr: true,
+		},
+		{
+			name: "dependencies on what the example doesn't define",
+			hcl: `resource "aws_secretsmanager_secret" "example" {
+  name       = "example"
+  depends_on = [aws_iam_role.example]
+}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid HCL",
+			hcl:     `resource "aws_secretsmanager_secret" "example" {`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testResource(t)
+
+			got, err := r.ModuleExample(Example{Title: tt.name, HCL: tt.hcl, Line: 1}, "../..")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ModuleExample() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			parseHCL(t, got)
+
+			for _, want := range tt.want {
+				if !strings.Contains(squash(got), want) {
+					t.Errorf("ModuleExample() is missing %q:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(squash(got), notWant) {
+					t.Errorf("ModuleExample() has %q:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package terraform
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// errLocked is returned by tryLockFile when another process holds the lock.
+var errLocked = errors.New("file is locked by another process")
+
+// lockPollInterval is how often a held lock is retried.
+const lockPollInterval = 250 * time.Millisecond
+
+// fileLock is an advisory, exclusive lock on a file, shared between
+// processes. The lock file itself is left in place, as removing it would
+// race with other processes opening it.
+type fileLock struct {
+	f *os.File
+}
+
+// lockFile takes the lock on path, creating it if needed, and waiting up to
+// timeout for other processes to release it.
+func lockFile(path string, timeout time.Duration) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err = tryLockFile(f)
+		if err == nil {
+			return &fileLock{f: f}, nil
+		}
+
+		if !errors.Is(err, errLocked) || time.Now().After(deadline) {
+			f.Close()
+			return nil, err
+		}
+
+		time.Sleep(lockPollInterval)
+	}
+}
+
+func (l *fileLock) Unlock() error {
+	if err := unlockFile(l.f); err != nil {
+		l.f.Close()
+		return fmt.Errorf("failed to unlock %s: %w", l.f.Name(), err)
+	}
+
+	return l.f.Close()
+}
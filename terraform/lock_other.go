@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package terraform
+
+import (
+	"os"
+)
+
+// Advisory locks aren't supported here; concurrent runs aren't protected.
+func tryLockFile(*os.File) error {
+	return nil
+}
+
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/infracasts/terraform-provider-aws-expose-internal/provider"
 	"log"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -56,6 +56,29 @@ func (l *ArgumentList) Append(item InputEntry) {
 	l.keys[item.Name] = struct{}{}
 }
 
+// NestedEntryList holds the documented fields of nested blocks, in the
+// order they're documented in.
+type NestedEntryList struct {
+	Entries []IOEntry
+	keys    map[string]int
+}
+
+func NewNestedEntryList() *NestedEntryList {
+	return &NestedEntryList{
+		Entries: make([]IOEntry, 0),
+		keys:    make(map[string]int),
+	}
+}
+
+func (l *NestedEntryList) Append(item IOEntry) {
+	if _, ok := l.keys[item.Path()]; ok {
+		return
+	}
+
+	l.keys[item.Path()] = len(l.Entries)
+	l.Entries = append(l.Entries, item)
+}
+
 type TFResource struct {
 	*Module
 	Name          string
@@ -65,38 +88,146 @@ type TFResource struct {
 	DocerizedType string
 	DocPath       string
 	AbsolutePath  string
+	Import        ImportDocs
+	// ImportBlock adds examples/import, a root configuration calling the
+	// module with an import block for the resource, taking its ID from a
+	// variable.
+	ImportBlock bool
+	// WriteExamples adds examples of calling the module, from the docs' Example
+	// Usage section.
+	WriteExamples bool
+	// EchoOutputs adds outputs of the arguments the provider doesn't
+	// compute, echoing the module's inputs.
+	EchoOutputs bool
+	// VariableOrder is the order of the module's variables and the
+	// arguments of its resource block, one of VariableOrders.
+	VariableOrder string
+	// GroupVariables groups the variables and arguments under comment
+	// banners, by whether they're required, optional, nested blocks or
+	// deprecated.
+	GroupVariables bool
 	*schema.Resource
-	arguments  *ArgumentList
-	attributes *AttributeList
+	arguments   *ArgumentList
+	attributes  *AttributeList
+	nested      *NestedEntryList
+	examples    []Example
+	diagnostics []Diagnostic
+	// docPaths are the paths parsed from the docs, recorded before
+	// AppendSchemaEntries drops the attributes the schema doesn't compute.
+	docPaths map[string]bool
+}
+
+// ImportDocs is what the docs' Import section says about importing existing
+// resources.
+type ImportDocs struct {
+	// Description explains the ID format, e.g. "aws_secretsmanager_secret
+	// can be imported by using the secret Amazon Resource Name (ARN), e.g.,"
+	Description string
+	// Example is the example ID given, e.g.
+	// arn:aws:secretsmanager:us-east-1:123456789012:secret:example-123456
+	Example string
 }
 
 func NewTFResource() TFResource {
 	return TFResource{
 		arguments:  NewArgumentList(),
 		attributes: NewAttributeList(),
+		nested:     NewNestedEntryList(),
 	}
 }
 
-func (r *TFResource) SetHashicorpResource() error {
+func (r *TFResource) SetHashicorpResource(source SchemaSource) error {
 	var (
-		hcProvider *schema.Provider
-		ok         bool
-		err        error
+		providerSchema *ProviderSchema
+		ok             bool
+		err            error
 	)
 
-	hcProvider, err = provider.New(context.Background())
+	providerSchema, err = source.ProviderSchema(context.Background())
 	if err != nil {
-		return fmt.Errorf("failed to initialize hashicorp terraform provider: %w", err)
+		return err
 	}
 
-	r.Resource, ok = hcProvider.ResourcesMap[r.Type]
+	r.Resource, ok = providerSchema.Resources[r.Type]
 	if !ok {
-		return fmt.Errorf("failed to discover resource of type %s in resource map", r.Name)
+		return fmt.Errorf("failed to discover resource of type %s in resource map", r.Type)
 	}
 
 	return err
 }
 
+// NestedSchema finds the schema of an argument or attribute, following
+// nested blocks for longer paths (e.g. replica, region).
+func (r TFResource) NestedSchema(path ...string) (*schema.Schema, error) {
+	var (
+		s  *schema.Schema
+		ok bool
+	)
+
+	fields := r.Schema
+	for i, name := range path {
+		if s, ok = fields[name]; !ok {
+			return nil, fmt.Errorf("%s has no argument or attribute %s", r.Type, strings.Join(path[:i+1], "."))
+		}
+
+		if i == len(path)-1 {
+			break
+		}
+
+		nested, ok := s.Elem.(*schema.Resource)
+		if !ok {
+			return nil, fmt.Errorf("%s.%s is not a nested block", r.Type, strings.Join(path[:i+1], "."))
+		}
+		fields = nested.Schema
+	}
+
+	return s, nil
+}
+
+// NestedBlockPath finds the nested block a docs subsection is about, by
+// name: within the block of the enclosing subsection first, then at the top
+// level, then anywhere in the schema. It returns nil if there's no such
+// block.
+func (r TFResource) NestedBlockPath(parent []string, name string) []string {
+	if len(parent) > 0 {
+		if s, err := r.NestedSchema(append(append([]string{}, parent...), name)...); err == nil && isNestedBlock(s) {
+			return append(append([]string{}, parent...), name)
+		}
+	}
+
+	return findNestedBlock(r.Schema, nil, name)
+}
+
+// findNestedBlock searches fields breadth first, in name order, for a nested
+// block called name.
+func findNestedBlock(fields map[string]*schema.Schema, parent []string, name string) []string {
+	if s, ok := fields[name]; ok && isNestedBlock(s) {
+		return append(append([]string{}, parent...), name)
+	}
+
+	names := make([]string, 0, len(fields))
+	for fieldName, s := range fields {
+		if isNestedBlock(s) {
+			names = append(names, fieldName)
+		}
+	}
+	sort.Strings(names)
+
+	for _, fieldName := range names {
+		nested := fields[fieldName].Elem.(*schema.Resource)
+		if path := findNestedBlock(nested.Schema, append(append([]string{}, parent...), fieldName), name); path != nil {
+			return path
+		}
+	}
+
+	return nil
+}
+
+func isNestedBlock(s *schema.Schema) bool {
+	_, ok := s.Elem.(*schema.Resource)
+	return ok
+}
+
 func (r *TFResource) AppendArgument(entry InputEntry) {
 	r.arguments.Append(entry)
 }
@@ -105,6 +236,51 @@ func (r *TFResource) AppendAttribute(entry OutputEntry) {
 	r.attributes.Append(entry)
 }
 
+// AppendNestedEntry records the docs of a field of a nested block.
+func (r *TFResource) AppendNestedEntry(entry IOEntry) {
+	r.nested.Append(entry)
+}
+
+// NestedEntry finds the docs of a field of a nested block, by its path
+// (e.g. replica, region).
+func (r TFResource) NestedEntry(path ...string) (IOEntry, bool) {
+	i, ok := r.nested.keys[strings.Join(path, ".")]
+	if !ok {
+		return IOEntry{}, false
+	}
+
+	return r.nested.Entries[i], true
+}
+
+// NestedEntries returns the documented fields of the nested block at the
+// path (e.g. replica).
+func (r TFResource) NestedEntries(path ...string) []IOEntry {
+	var entries []IOEntry
+	for _, entry := range r.nested.Entries {
+		if strings.Join(entry.Parent, ".") == strings.Join(path, ".") {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// ImportIDVariable is the name of the variable holding the ID to import.
+func (r TFResource) ImportIDVariable() string {
+	if r.VarPrefix == "" {
+		return "import_id"
+	}
+	return strings.Join([]string{r.VarPrefix, "import_id"}, "_")
+}
+
+func (r TFResource) ImportIDDescription() string {
+	description := fmt.Sprintf("ID of an existing %s to import as module.%s.%s.%s", r.Type, r.Name, r.Type, r.Name)
+	if r.Import.Example != "" {
+		description += fmt.Sprintf(", e.g. %s", r.Import.Example)
+	}
+	return description + "."
+}
+
 func (r TFResource) MaxAttributeLength() int {
 	var i, itemLen int
 	for _, item := range r.Attributes() {
@@ -163,17 +339,53 @@ func (r *TFResource) Create() error {
 		return fmt.Errorf("failed to generate template: %w", err)
 	}
 
+	// create examples/*/main.tf
+	if r.WriteExamples {
+		if err := r.createExamples(); err != nil {
+			return err
+		}
+	}
+
+	// create examples/import/main.tf and README.md, explaining how to import
+	if r.ImportBlock {
+		if err := r.createImportExample(); err != nil {
+			return err
+		}
+
+		readmeTemplatable := NewTFReadme(r)
+		if err := createOrAppendToFile(readmeTemplatable); err != nil {
+			return fmt.Errorf("failed to generate template: %w", err)
+		}
+	}
+
 	return nil
 }
 
 type IOEntry struct {
 	*schema.Schema
-	Resource    *TFResource
+	Resource *TFResource
+	// Parent is the path of the nested block the entry is a field of, if
+	// any (e.g. replica, for replica.region).
+	Parent      []string
 	Name        string
 	TFType      string
 	Optional    bool
 	Deprecated  bool
 	Description string
+	// Constraint is the values the docs say the entry accepts, if any.
+	Constraint *Constraint
+	// DocDefault is the default the docs state, as an HCL literal, for
+	// arguments whose schema has none (e.g. applied server side).
+	DocDefault string
+	// DocLine is the line of the docs the entry is documented on, and
+	// DocText its raw markdown.
+	DocLine int
+	DocText string
+}
+
+// Path is the full path of the entry, e.g. replica.region.
+func (e IOEntry) Path() string {
+	return strings.Join(append(append([]string{}, e.Parent...), e.Name), ".")
 }
 
 func (e IOEntry) Padding(max int) string {
@@ -219,6 +431,13 @@ func formatType(s *schema.Schema) string {
 }
 
 func (e IOEntry) DefaultValue() string {
+	if def := e.schemaDefault(); def != "" {
+		return def
+	}
+	return e.DocDefault
+}
+
+func (e IOEntry) schemaDefault() string {
 	if e.Schema == nil {
 		return ""
 	} else {
@@ -232,6 +451,17 @@ func (e IOEntry) DefaultValue() string {
 	}
 }
 
+// DefaultSource is where DefaultValue came from, DefaultSourceSchema or
+// DefaultSourceDocs, if there is one.
+func (e IOEntry) DefaultSource() string {
+	if e.schemaDefault() != "" {
+		return DefaultSourceSchema
+	} else if e.DocDefault != "" {
+		return DefaultSourceDocs
+	}
+	return ""
+}
+
 // Arguments
 type InputEntry IOEntry
 
@@ -247,10 +477,47 @@ func (e InputEntry) DefaultValue() string {
 	return IOEntry(e).DefaultValue()
 }
 
+// VariableDescription is the description of the entry's variable, noting
+// where its default came from.
+func (e InputEntry) VariableDescription() string {
+	switch IOEntry(e).DefaultSource() {
+	case DefaultSourceSchema:
+		return strings.TrimSpace(e.Description + " Default from the provider schema.")
+	case DefaultSourceDocs:
+		return strings.TrimSpace(e.Description + " Default from the provider docs.")
+	default:
+		return e.Description
+	}
+}
+
 func (e InputEntry) PrefixedName() string {
 	return strings.Join([]string{e.Resource.VarPrefix, e.Name}, "_")
 }
 
+// ValidationCondition checks the variable against the entry's constraint.
+func (e InputEntry) ValidationCondition() string {
+	if e.Constraint == nil {
+		return ""
+	}
+
+	variable := "var." + e.PrefixedName()
+	condition := e.Constraint.Condition(e.Schema, variable)
+	if e.Schema.Type == schema.TypeList || e.Schema.Type == schema.TypeSet {
+		condition = fmt.Sprintf("alltrue([for v in %s : %s])", variable, e.Constraint.Condition(e.Schema, "v"))
+	}
+
+	// conditionals short circuit, unlike ||
+	return fmt.Sprintf("%s == null ? true : (%s)", variable, condition)
+}
+
+func (e InputEntry) ValidationMessage() string {
+	if e.Constraint == nil {
+		return ""
+	}
+
+	return e.Constraint.ErrorMessage(e.Name)
+}
+
 // Attributes
 type OutputEntry IOEntry
 
@@ -266,6 +533,12 @@ func (e OutputEntry) DefaultValue() string {
 	return IOEntry(e).DefaultValue()
 }
 
+// IsSensitive reports whether the output must be marked sensitive, as the
+// schema's field is.
+func (e OutputEntry) IsSensitive() bool {
+	return e.Schema != nil && e.Schema.Sensitive
+}
+
 func (e OutputEntry) PrefixedName() string {
 	return strings.Join([]string{e.Resource.AttrPrefix, e.Name}, "_")
 }
@@ -382,6 +655,42 @@ func (in TFInput) Create(file *os.File) error {
 	return nil
 }
 
+// TFReadme documents how to use the generated module
+type TFReadme struct {
+	*TFResource
+	templateName string
+	filename     string
+}
+
+func NewTFReadme(resource *TFResource) TFReadme {
+	return TFReadme{
+		templateName: "readme",
+		TFResource:   resource,
+		filename:     "README.md",
+	}
+}
+
+func (rm TFReadme) TemplateName() string {
+	return rm.templateName
+}
+
+func (rm TFReadme) FilePath() string {
+	return fmt.Sprintf("%s/%s", rm.AbsolutePath, rm.filename)
+}
+
+func (rm TFReadme) Template() *template.Template {
+	t := template.New(rm.TemplateName())
+	return template.Must(t.Parse(string(tpl.ReadmeTemplate())))
+}
+
+func (rm TFReadme) Create(file *os.File) error {
+	err := rm.Template().Execute(file, rm)
+	if err != nil {
+		return fmt.Errorf("failed to execute %s template: %w", rm.TemplateName(), err)
+	}
+	return nil
+}
+
 type Templatable interface {
 	TemplateName() string
 	FilePath() string
@@ -424,3 +733,20 @@ func createOrAppendToFile(templatable Templatable) error {
 
 	return nil
 }
+
+// createOrTruncateFile writes the templatable's file, replacing whatever was
+// there, for files that must only ever hold one copy (e.g. examples).
+func createOrTruncateFile(templatable Templatable) error {
+	log.Println("writing ", templatable.FilePath())
+	outputFile, err := os.Create(templatable.FilePath())
+	if err != nil {
+		return fmt.Errorf("failed to create %s file: %w", templatable.TemplateName(), err)
+	}
+	defer outputFile.Close()
+
+	if err := templatable.Create(outputFile); err != nil {
+		return fmt.Errorf("failed to generate file: %w", err)
+	}
+
+	return nil
+}
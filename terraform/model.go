@@ -0,0 +1,107 @@
+package terraform
+
+// ResourceModel is the parsed model of a resource, combining its schema and
+// docs, as emitted by terrawrap inspect. Its field names are kept stable for
+// tools built on top of it.
+type ResourceModel struct {
+	Type        string       `json:"type" yaml:"type"`
+	DocsVersion string       `json:"docs_version" yaml:"docs_version"`
+	DocPath     string       `json:"doc_path" yaml:"doc_path"`
+	Arguments   []EntryModel `json:"arguments" yaml:"arguments"`
+	Attributes  []EntryModel `json:"attributes" yaml:"attributes"`
+	Import      *ImportModel `json:"import,omitempty" yaml:"import,omitempty"`
+	Examples    []Example    `json:"examples" yaml:"examples"`
+	Diagnostics []Diagnostic `json:"diagnostics" yaml:"diagnostics"`
+}
+
+// ImportModel is the ID format described by the docs' Import section.
+type ImportModel struct {
+	Description string `json:"description" yaml:"description"`
+	Example     string `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+// EntryModel is one argument or attribute of a ResourceModel; flags other
+// than Deprecated only come from the schema, so are all false when InSchema
+// isn't.
+type EntryModel struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is the terraform type of the variable or output generated for
+	// the entry, e.g. list(string).
+	Type       string `json:"type" yaml:"type"`
+	InSchema   bool   `json:"in_schema" yaml:"in_schema"`
+	Required   bool   `json:"required" yaml:"required"`
+	Optional   bool   `json:"optional" yaml:"optional"`
+	Computed   bool   `json:"computed" yaml:"computed"`
+	ForceNew   bool   `json:"force_new" yaml:"force_new"`
+	Sensitive  bool   `json:"sensitive" yaml:"sensitive"`
+	Deprecated bool   `json:"deprecated" yaml:"deprecated"`
+	Default    string `json:"default,omitempty" yaml:"default,omitempty"`
+	// DefaultSource is where Default came from, schema or docs.
+	DefaultSource string   `json:"default_source,omitempty" yaml:"default_source,omitempty"`
+	ConflictsWith []string `json:"conflicts_with,omitempty" yaml:"conflicts_with,omitempty"`
+	Description   string   `json:"description" yaml:"description"`
+	// Constraint is the values the docs say the entry accepts, where the
+	// schema agrees.
+	Constraint *Constraint `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+	// Fields are the documented fields of a nested block.
+	Fields []EntryModel `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// Model returns the parsed model of the resource, with entries in the order
+// they're documented in, noting the version of the docs it was parsed from.
+func (r TFResource) Model(docsVersion string) ResourceModel {
+	model := ResourceModel{
+		Type:        r.Type,
+		DocsVersion: docsVersion,
+		DocPath:     r.DocPath,
+		Arguments:   make([]EntryModel, 0, len(r.Arguments())),
+		Attributes:  make([]EntryModel, 0, len(r.Attributes())),
+		Examples:    append(make([]Example, 0, len(r.examples)), r.examples...),
+		Diagnostics: append(make([]Diagnostic, 0, len(r.diagnostics)), r.diagnostics...),
+	}
+
+	if r.Import.Description != "" || r.Import.Example != "" {
+		model.Import = &ImportModel{Description: r.Import.Description, Example: r.Import.Example}
+	}
+
+	for _, argument := range r.Arguments() {
+		model.Arguments = append(model.Arguments, IOEntry(argument).model())
+	}
+
+	for _, attribute := range r.Attributes() {
+		model.Attributes = append(model.Attributes, IOEntry(attribute).model())
+	}
+
+	return model
+}
+
+func (e IOEntry) model() EntryModel {
+	model := EntryModel{
+		Name:          e.Name,
+		Type:          e.ValueType(),
+		Deprecated:    e.Deprecated,
+		Default:       e.DefaultValue(),
+		DefaultSource: e.DefaultSource(),
+		Description:   e.Description,
+		Constraint:    e.Constraint,
+	}
+
+	if e.Schema != nil {
+		model.InSchema = true
+		model.Required = e.Schema.Required
+		model.Optional = e.Schema.Optional
+		model.Computed = e.Schema.Computed
+		model.ForceNew = e.Schema.ForceNew
+		model.Sensitive = e.Schema.Sensitive
+		model.Deprecated = e.Deprecated || e.Schema.Deprecated != ""
+		model.ConflictsWith = e.Schema.ConflictsWith
+	}
+
+	if e.Resource != nil {
+		for _, field := range e.Resource.NestedEntries(append(append([]string{}, e.Parent...), e.Name)...) {
+			model.Fields = append(model.Fields, field.model())
+		}
+	}
+
+	return model
+}
@@ -0,0 +1,111 @@
+package terraform
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Orders of the module's variables, and the arguments of its resource block.
+const (
+	// VariableOrderDocs keeps the order of the docs, with arguments only in
+	// the schema last.
+	VariableOrderDocs = "docs"
+	// VariableOrderRequiredFirst puts required arguments first, otherwise
+	// keeping the order of the docs.
+	VariableOrderRequiredFirst = "required-first"
+	VariableOrderAlphabetical  = "alphabetical"
+	// VariableOrderSchema is the order terraform providers schema lists
+	// them in: attributes by name, then nested blocks by name.
+	VariableOrderSchema = "schema"
+)
+
+var VariableOrders = []string{VariableOrderDocs, VariableOrderRequiredFirst, VariableOrderAlphabetical, VariableOrderSchema}
+
+// CheckVariableOrder fails for an order that isn't one of VariableOrders.
+func CheckVariableOrder(order string) error {
+	if order == "" || contains(VariableOrders, order) {
+		return nil
+	}
+	return fmt.Errorf("unknown variable order %q, expected one of %v", order, VariableOrders)
+}
+
+// ArgumentGroup is a run of arguments under a comment banner, or without one
+// when they aren't grouped.
+type ArgumentGroup struct {
+	Title     string
+	Arguments []InputEntry
+}
+
+// argumentGroupTitles are the banners of grouped arguments, in the order the
+// groups are written in.
+var argumentGroupTitles = []string{"Required", "Optional", "Nested blocks", "Deprecated"}
+
+// ArgumentGroups are the arguments in VariableOrder, split into groups under
+// banners if GroupVariables is set. Groups without arguments are left out.
+func (r TFResource) ArgumentGroups() []ArgumentGroup {
+	arguments := r.orderedArguments()
+	if !r.GroupVariables {
+		return []ArgumentGroup{{Arguments: arguments}}
+	}
+
+	byTitle := make(map[string][]InputEntry)
+	for _, argument := range arguments {
+		title := argumentGroupTitle(argument)
+		byTitle[title] = append(byTitle[title], argument)
+	}
+
+	var groups []ArgumentGroup
+	for _, title := range argumentGroupTitles {
+		if len(byTitle[title]) > 0 {
+			groups = append(groups, ArgumentGroup{Title: title, Arguments: byTitle[title]})
+		}
+	}
+
+	return groups
+}
+
+func argumentGroupTitle(argument InputEntry) string {
+	switch {
+	case argument.Deprecated || (argument.Schema != nil && argument.Schema.Deprecated != ""):
+		return "Deprecated"
+	case argument.Schema != nil && isNestedBlock(argument.Schema):
+		return "Nested blocks"
+	case argument.Schema != nil && argument.Schema.Required:
+		return "Required"
+	default:
+		return "Optional"
+	}
+}
+
+func (r TFResource) orderedArguments() []InputEntry {
+	arguments := append([]InputEntry{}, r.Arguments()...)
+
+	switch r.VariableOrder {
+	case VariableOrderRequiredFirst:
+		sort.SliceStable(arguments, func(i, j int) bool {
+			return isRequired(arguments[i]) && !isRequired(arguments[j])
+		})
+	case VariableOrderAlphabetical:
+		sort.SliceStable(arguments, func(i, j int) bool {
+			return arguments[i].Name < arguments[j].Name
+		})
+	case VariableOrderSchema:
+		sort.SliceStable(arguments, func(i, j int) bool {
+			iBlock, jBlock := isBlockArgument(arguments[i]), isBlockArgument(arguments[j])
+			if iBlock != jBlock {
+				return jBlock
+			}
+			return arguments[i].Name < arguments[j].Name
+		})
+	}
+
+	return arguments
+}
+
+func isRequired(argument InputEntry) bool {
+	return argument.Schema != nil && argument.Schema.Required
+}
+
+func isBlockArgument(argument InputEntry) bool {
+	return argument.Schema != nil && isNestedBlock(argument.Schema)
+}
@@ -0,0 +1,124 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// testOrderResource documents its arguments in an order that's neither
+// required first, alphabetical nor the schema's.
+func testOrderResource(t *testing.T, order string, group bool) *TFResource {
+	t.Helper()
+
+	r := testDocumentedResource(t)
+	r.Schema["zone"] = &schema.Schema{Type: schema.TypeString, Optional: true}
+	r.Schema["kms_key_id"] = &schema.Schema{Type: schema.TypeString, Optional: true, Deprecated: "use key_id"}
+	for _, name := range []string{"rotation_rules", "zone", "description", "name", "kms_key_id"} {
+		s := r.Schema[name]
+		r.AppendArgument(InputEntry{Schema: s, Resource: r, Name: name, Deprecated: s.Deprecated != ""})
+	}
+	r.VariableOrder = order
+	r.GroupVariables = group
+
+	return r
+}
+
+func TestOrderedArguments(t *testing.T) {
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{order: "", want: []string{"rotation_rules", "zone", "description", "name", "kms_key_id"}},
+		{order: VariableOrderDocs, want: []string{"rotation_rules", "zone", "description", "name", "kms_key_id"}},
+		{order: VariableOrderRequiredFirst, want: []string{"name", "rotation_rules", "zone", "description", "kms_key_id"}},
+		{order: VariableOrderAlphabetical, want: []string{"description", "kms_key_id", "name", "rotation_rules", "zone"}},
+		// attributes by name, then nested blocks by name
+		{order: VariableOrderSchema, want: []string{"description", "kms_key_id", "name", "zone", "rotation_rules"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			var got []string
+			for _, argument := range testOrderResource(t, tt.order, false).orderedArguments() {
+				got = append(got, argument.Name)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderedArguments() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArgumentGroups(t *testing.T) {
+	tests := []struct {
+		name  string
+		order string
+		group bool
+		want  map[string][]string
+	}{
+		{
+			name: "ungrouped",
+			want: map[string][]string{"": {"rotation_rules", "zone", "description", "name", "kms_key_id"}},
+		},
+		{
+			name:  "grouped",
+			group: true,
+			want: map[string][]string{
+				"Required":      {"name"},
+				"Optional":      {"zone", "description"},
+				"Nested blocks": {"rotation_rules"},
+				"Deprecated":    {"kms_key_id"},
+			},
+		},
+		{
+			name:  "grouped alphabetically",
+			order: VariableOrderAlphabetical,
+			group: true,
+			want: map[string][]string{
+				"Required":      {"name"},
+				"Optional":      {"description", "zone"},
+				"Nested blocks": {"rotation_rules"},
+				"Deprecated":    {"kms_key_id"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := testOrderResource(t, tt.order, tt.group).ArgumentGroups()
+
+			got := make(map[string][]string)
+			var titles []string
+			for _, group := range groups {
+				titles = append(titles, group.Title)
+				for _, argument := range group.Arguments {
+					got[group.Title] = append(got[group.Title], argument.Name)
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ArgumentGroups() = %v, want %v", got, tt.want)
+			}
+
+			// groups are in the order of their titles
+			if tt.group && !reflect.DeepEqual(titles, argumentGroupTitles) {
+				t.Errorf("ArgumentGroups() titles = %v, want %v", titles, argumentGroupTitles)
+			}
+		})
+	}
+}
+
+func TestCheckVariableOrder(t *testing.T) {
+	for _, order := range append([]string{""}, VariableOrders...) {
+		if err := CheckVariableOrder(order); err != nil {
+			t.Errorf("CheckVariableOrder(%q) error = %v", order, err)
+		}
+	}
+
+	if err := CheckVariableOrder("random"); err == nil {
+		t.Error("CheckVariableOrder(\"random\") succeeded")
+	}
+}
@@ -0,0 +1,530 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Per github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server/server.go
+const (
+	pluginMagicCookieKey   = "TF_PLUGIN_MAGIC_COOKIE"
+	pluginMagicCookieValue = "d602bf8f470bc67ca7faa0386276bbdd4330efaf76d1a219cb4d6991ca9872b2"
+	pluginProtocolVersion  = 5
+	getSchemaMethod        = "/tfplugin5.Provider/GetSchema"
+)
+
+// e.g. terraform-provider-aws_v4.29.0_x5
+var pluginVersionFormat = regexp.MustCompile(`^terraform-provider-[A-Za-z0-9]+_(?P<Version>v[0-9]+\.[0-9]+\.[0-9]+[^_]*)`)
+
+// PluginSchemaSource reads schemas from an installed provider binary (e.g.
+// one under .terraform/providers or the plugin cache), by launching it as a
+// plugin and calling GetProviderSchema over gRPC.
+type PluginSchemaSource struct {
+	Path  string
+	cache schemaCache
+}
+
+func NewPluginSchemaSource(binaryPath string) *PluginSchemaSource {
+	return &PluginSchemaSource{Path: binaryPath}
+}
+
+func (s *PluginSchemaSource) ProviderSchema(ctx context.Context) (*ProviderSchema, error) {
+	return s.cache.load(func() (*ProviderSchema, error) {
+		resp, err := s.getProviderSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ps, err := providerSchemaFromProto(resp)
+		if err != nil {
+			return nil, err
+		}
+		ps.Version = s.Version()
+
+		return ps, nil
+	})
+}
+
+// Version returns the provider release, going by the binary's file name as
+// installed by terraform init.
+func (s *PluginSchemaSource) Version() string {
+	subMatches := pluginVersionFormat.FindStringSubmatch(filepath.Base(s.Path))
+	if len(subMatches) < 1 {
+		return ""
+	}
+
+	return subMatches[pluginVersionFormat.SubexpIndex("Version")]
+}
+
+func (s *PluginSchemaSource) getProviderSchema(ctx context.Context) (*tfprotov5.GetProviderSchemaResponse, error) {
+	if _, err := os.Stat(s.Path); err != nil {
+		return nil, fmt.Errorf("failed to find provider binary %s: %w", s.Path, err)
+	}
+
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: plugin.HandshakeConfig{
+			ProtocolVersion:  pluginProtocolVersion,
+			MagicCookieKey:   pluginMagicCookieKey,
+			MagicCookieValue: pluginMagicCookieValue,
+		},
+		Plugins:          plugin.PluginSet{"provider": &grpcProviderPlugin{}},
+		Cmd:              exec.Command(s.Path),
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		SyncStdout:       io.Discard,
+		SyncStderr:       io.Discard,
+		Logger: hclog.New(&hclog.LoggerOptions{
+			Name:   "plugin",
+			Level:  hclog.Error,
+			Output: os.Stderr,
+		}),
+	})
+	defer client.Kill()
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch provider plugin %s (only protocol v5 providers are supported): %w", s.Path, err)
+	}
+
+	raw, err := rpcClient.Dispense("provider")
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispense provider plugin %s: %w", s.Path, err)
+	}
+
+	conn := raw.(*grpc.ClientConn)
+
+	var out rawMessage
+	// Provider schemas (AWS in particular) are far larger than the gRPC
+	// default receive limit.
+	err = conn.Invoke(ctx, getSchemaMethod, rawMessage{}, &out,
+		grpc.ForceCodec(rawCodec{}), grpc.MaxCallRecvMsgSize(256<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema from provider plugin %s: %w", s.Path, err)
+	}
+
+	resp, err := decodeGetProviderSchemaResponse(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode schema from provider plugin %s: %w", s.Path, err)
+	}
+
+	return resp, nil
+}
+
+// grpcProviderPlugin hands back the raw client connection; the tfplugin5
+// generated client is internal to terraform-plugin-go, so messages are
+// encoded by hand below.
+type grpcProviderPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+}
+
+func (p *grpcProviderPlugin) GRPCServer(*plugin.GRPCBroker, *grpc.Server) error {
+	return fmt.Errorf("terrawrap does not serve providers")
+}
+
+func (p *grpcProviderPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, conn *grpc.ClientConn) (interface{}, error) {
+	return conn, nil
+}
+
+// rawMessage is an already-encoded protobuf message.
+type rawMessage []byte
+
+// rawCodec passes protobuf wire bytes through untouched. It's registered as
+// "proto" so the provider decodes requests with its usual codec.
+type rawCodec struct{}
+
+func (rawCodec) Name() string { return "proto" }
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(rawMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return msg, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*rawMessage)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+// protoFields walks the top level fields of an encoded message, per
+// tfplugin5.proto.
+func protoFields(b []byte, fn func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		n, err := fn(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			// not a field we're interested in
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+	}
+
+	return nil
+}
+
+func consumeBytes(b []byte, dst *[]byte) (int, error) {
+	v, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return n, protowire.ParseError(n)
+	}
+	*dst = v
+	return n, nil
+}
+
+func consumeVarint(b []byte, dst *uint64) (int, error) {
+	v, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return n, protowire.ParseError(n)
+	}
+	*dst = v
+	return n, nil
+}
+
+func decodeGetProviderSchemaResponse(b []byte) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp := &tfprotov5.GetProviderSchemaResponse{
+		ResourceSchemas:   make(map[string]*tfprotov5.Schema),
+		DataSourceSchemas: make(map[string]*tfprotov5.Schema),
+	}
+
+	err := protoFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType {
+			return -1, nil
+		}
+
+		var v []byte
+		n, err := consumeBytes(b, &v)
+		if err != nil {
+			return n, err
+		}
+
+		switch num {
+		case 2, 3: // resource_schemas, data_source_schemas
+			name, s, err := decodeSchemaMapEntry(v)
+			if err != nil {
+				return n, err
+			}
+			if num == 2 {
+				resp.ResourceSchemas[name] = s
+			} else {
+				resp.DataSourceSchemas[name] = s
+			}
+		case 4: // diagnostics
+			diag, err := decodeDiagnostic(v)
+			if err != nil {
+				return n, err
+			}
+			resp.Diagnostics = append(resp.Diagnostics, diag)
+		}
+
+		return n, nil
+	})
+
+	return resp, err
+}
+
+func decodeSchemaMapEntry(b []byte) (string, *tfprotov5.Schema, error) {
+	var (
+		name string
+		s    = &tfprotov5.Schema{}
+	)
+
+	err := protoFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType {
+			return -1, nil
+		}
+
+		var v []byte
+		n, err := consumeBytes(b, &v)
+		if err != nil {
+			return n, err
+		}
+
+		switch num {
+		case 1:
+			name = string(v)
+		case 2:
+			s, err = decodeSchema(v)
+		}
+
+		return n, err
+	})
+
+	return name, s, err
+}
+
+func decodeSchema(b []byte) (*tfprotov5.Schema, error) {
+	s := &tfprotov5.Schema{}
+
+	err := protoFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			var v uint64
+			n, err := consumeVarint(b, &v)
+			s.Version = int64(v)
+			return n, err
+		case num == 2 && typ == protowire.BytesType:
+			var v []byte
+			n, err := consumeBytes(b, &v)
+			if err != nil {
+				return n, err
+			}
+			s.Block, err = decodeBlock(v)
+			return n, err
+		}
+
+		return -1, nil
+	})
+
+	return s, err
+}
+
+func decodeBlock(b []byte) (*tfprotov5.SchemaBlock, error) {
+	block := &tfprotov5.SchemaBlock{}
+
+	err := protoFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		var (
+			n   int
+			err error
+			u   uint64
+			v   []byte
+		)
+
+		switch typ {
+		case protowire.VarintType:
+			n, err = consumeVarint(b, &u)
+		case protowire.BytesType:
+			n, err = consumeBytes(b, &v)
+		default:
+			return -1, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		switch num {
+		case 1:
+			block.Version = int64(u)
+		case 2:
+			var attr *tfprotov5.SchemaAttribute
+			if attr, err = decodeAttribute(v); err == nil {
+				block.Attributes = append(block.Attributes, attr)
+			}
+		case 3:
+			var nested *tfprotov5.SchemaNestedBlock
+			if nested, err = decodeNestedBlock(v); err == nil {
+				block.BlockTypes = append(block.BlockTypes, nested)
+			}
+		case 4:
+			block.Description = string(v)
+		case 5:
+			block.DescriptionKind = tfprotov5.StringKind(u)
+		case 6:
+			block.Deprecated = u != 0
+		}
+
+		return n, err
+	})
+
+	return block, err
+}
+
+func decodeAttribute(b []byte) (*tfprotov5.SchemaAttribute, error) {
+	attr := &tfprotov5.SchemaAttribute{}
+
+	err := protoFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		var (
+			n   int
+			err error
+			u   uint64
+			v   []byte
+		)
+
+		switch typ {
+		case protowire.VarintType:
+			n, err = consumeVarint(b, &u)
+		case protowire.BytesType:
+			n, err = consumeBytes(b, &v)
+		default:
+			return -1, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		switch num {
+		case 1:
+			attr.Name = string(v)
+		case 2:
+			attr.Type, err = decodeType(v)
+		case 3:
+			attr.Description = string(v)
+		case 4:
+			attr.Required = u != 0
+		case 5:
+			attr.Optional = u != 0
+		case 6:
+			attr.Computed = u != 0
+		case 7:
+			attr.Sensitive = u != 0
+		case 8:
+			attr.DescriptionKind = tfprotov5.StringKind(u)
+		case 9:
+			attr.Deprecated = u != 0
+		}
+
+		return n, err
+	})
+
+	return attr, err
+}
+
+func decodeNestedBlock(b []byte) (*tfprotov5.SchemaNestedBlock, error) {
+	nested := &tfprotov5.SchemaNestedBlock{}
+
+	err := protoFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		var (
+			n   int
+			err error
+			u   uint64
+			v   []byte
+		)
+
+		switch typ {
+		case protowire.VarintType:
+			n, err = consumeVarint(b, &u)
+		case protowire.BytesType:
+			n, err = consumeBytes(b, &v)
+		default:
+			return -1, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		switch num {
+		case 1:
+			nested.TypeName = string(v)
+		case 2:
+			nested.Block, err = decodeBlock(v)
+		case 3:
+			nested.Nesting = tfprotov5.SchemaNestedBlockNestingMode(u)
+		case 4:
+			nested.MinItems = int64(u)
+		case 5:
+			nested.MaxItems = int64(u)
+		}
+
+		return n, err
+	})
+
+	return nested, err
+}
+
+func decodeDiagnostic(b []byte) (*tfprotov5.Diagnostic, error) {
+	diag := &tfprotov5.Diagnostic{}
+
+	err := protoFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		var (
+			n   int
+			err error
+			u   uint64
+			v   []byte
+		)
+
+		switch typ {
+		case protowire.VarintType:
+			n, err = consumeVarint(b, &u)
+		case protowire.BytesType:
+			n, err = consumeBytes(b, &v)
+		default:
+			return -1, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		switch num {
+		case 1:
+			diag.Severity = tfprotov5.DiagnosticSeverity(u)
+		case 2:
+			diag.Summary = string(v)
+		case 3:
+			diag.Detail = string(v)
+		}
+
+		return n, nil
+	})
+
+	return diag, err
+}
+
+// decodeType parses the JSON type constraint sent over the wire (e.g.
+// ["list","string"]).
+func decodeType(b []byte) (tftypes.Type, error) {
+	t, err := ctyjson.UnmarshalType(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse attribute type %s: %w", b, err)
+	}
+
+	return tftypesFromCty(t), nil
+}
+
+func tftypesFromCty(t cty.Type) tftypes.Type {
+	switch {
+	case t == cty.String:
+		return tftypes.String
+	case t == cty.Number:
+		return tftypes.Number
+	case t == cty.Bool:
+		return tftypes.Bool
+	case t.IsListType():
+		return tftypes.List{ElementType: tftypesFromCty(t.ElementType())}
+	case t.IsSetType():
+		return tftypes.Set{ElementType: tftypesFromCty(t.ElementType())}
+	case t.IsMapType():
+		return tftypes.Map{ElementType: tftypesFromCty(t.ElementType())}
+	case t.IsObjectType():
+		attrs := make(map[string]tftypes.Type)
+		for name, attrType := range t.AttributeTypes() {
+			attrs[name] = tftypesFromCty(attrType)
+		}
+		return tftypes.Object{AttributeTypes: attrs}
+	case t.IsTupleType():
+		elems := make([]tftypes.Type, 0, len(t.TupleElementTypes()))
+		for _, elemType := range t.TupleElementTypes() {
+			elems = append(elems, tftypesFromCty(elemType))
+		}
+		return tftypes.Tuple{ElementTypes: elems}
+	default:
+		return tftypes.DynamicPseudoType
+	}
+}
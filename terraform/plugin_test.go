@@ -0,0 +1,133 @@
+package terraform
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestDecodeType(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    tftypes.Type
+		wantErr bool
+	}{
+		{json: `"string"`, want: tftypes.String},
+		{json: `"number"`, want: tftypes.Number},
+		{json: `"bool"`, want: tftypes.Bool},
+		{json: `"dynamic"`, want: tftypes.DynamicPseudoType},
+		{json: `["list","string"]`, want: tftypes.List{ElementType: tftypes.String}},
+		{json: `["set","number"]`, want: tftypes.Set{ElementType: tftypes.Number}},
+		{json: `["map",["list","bool"]]`, want: tftypes.Map{ElementType: tftypes.List{ElementType: tftypes.Bool}}},
+		{
+			json: `["object",{"region":"string","days":"number"}]`,
+			want: tftypes.Object{AttributeTypes: map[string]tftypes.Type{"region": tftypes.String, "days": tftypes.Number}},
+		},
+		{json: `["tuple",["string","bool"]]`, want: tftypes.Tuple{ElementTypes: []tftypes.Type{tftypes.String, tftypes.Bool}}},
+		{json: `["list"]`, wantErr: true},
+		{json: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			got, err := decodeType([]byte(tt.json))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("decodeType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// protoMessage encodes fields, each a varint (uint64 or bool) or bytes
+// (string or []byte), in the order given.
+func protoMessage(fields ...interface{}) []byte {
+	var b []byte
+	for i := 0; i < len(fields); i += 2 {
+		num := protowire.Number(fields[i].(int))
+		switch v := fields[i+1].(type) {
+		case uint64:
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, v)
+		case bool:
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, protowire.EncodeBool(v))
+		case string:
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendString(b, v)
+		case []byte:
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendBytes(b, v)
+		}
+	}
+	return b
+}
+
+func TestDecodeGetProviderSchemaResponse(t *testing.T) {
+	block := protoMessage(
+		2, protoMessage(1, "name", 2, `"string"`, 3, "Name of the secret.", 4, true),
+		2, protoMessage(1, "arn", 2, `"string"`, 6, true, 7, true),
+		3, protoMessage(1, "replica", 2, protoMessage(2, protoMessage(1, "region", 2, `"string"`, 5, true)), 3, uint64(tfprotov5.SchemaNestedBlockNestingModeSet), 5, uint64(3)),
+		4, "A secret.",
+		6, true,
+		// unknown fields are skipped
+		99, uint64(1),
+	)
+
+	b := protoMessage(
+		// provider schema, which isn't needed
+		1, protoMessage(1, uint64(0)),
+		2, protoMessage(1, "aws_secretsmanager_secret", 2, protoMessage(1, uint64(1), 2, block)),
+		3, protoMessage(1, "aws_region", 2, protoMessage(2, protoMessage())),
+		4, protoMessage(1, uint64(tfprotov5.DiagnosticSeverityWarning), 2, "deprecated", 3, "use something else"),
+	)
+
+	resp, err := decodeGetProviderSchemaResponse(b)
+	if err != nil {
+		t.Fatalf("decodeGetProviderSchemaResponse() error = %v", err)
+	}
+
+	s, ok := resp.ResourceSchemas["aws_secretsmanager_secret"]
+	if !ok {
+		t.Fatalf("ResourceSchemas = %v, want aws_secretsmanager_secret", resp.ResourceSchemas)
+	}
+	if s.Version != 1 || s.Block.Description != "A secret." || !s.Block.Deprecated {
+		t.Errorf("schema = %+v, block = %+v", s, s.Block)
+	}
+	if _, ok := resp.DataSourceSchemas["aws_region"]; !ok {
+		t.Errorf("DataSourceSchemas = %v, want aws_region", resp.DataSourceSchemas)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Summary != "deprecated" || resp.Diagnostics[0].Detail != "use something else" {
+		t.Errorf("Diagnostics = %+v", resp.Diagnostics)
+	}
+
+	r := resourceFromProto(s)
+	resource := TFResource{Resource: r, Type: "aws_secretsmanager_secret"}
+	for _, path := range [][]string{{"name"}, {"arn"}, {"replica"}, {"replica", "region"}} {
+		if _, err := resource.NestedSchema(path...); err != nil {
+			t.Errorf("NestedSchema() error = %v", err)
+		}
+	}
+
+	if name := r.Schema["name"]; !name.Required || name.Description != "Name of the secret." {
+		t.Errorf("name = %+v", name)
+	}
+	if arn := r.Schema["arn"]; !arn.Computed || !arn.Sensitive {
+		t.Errorf("arn = %+v", arn)
+	}
+	if replica := r.Schema["replica"]; replica.MaxItems != 3 || !replica.Optional {
+		t.Errorf("replica = %+v", replica)
+	}
+}
+
+func TestDecodeGetProviderSchemaResponseTruncated(t *testing.T) {
+	b := protoMessage(2, protoMessage(1, "aws_secretsmanager_secret"))
+
+	if _, err := decodeGetProviderSchemaResponse(b[:len(b)-3]); err == nil {
+		t.Error("decodeGetProviderSchemaResponse() succeeded on a truncated message")
+	}
+}
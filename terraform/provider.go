@@ -2,10 +2,11 @@ package terraform
 
 import (
 	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log"
-	"net/http"
 	"net/url"
 	"os"
 	"path"
@@ -20,54 +21,287 @@ var providerDefaults = map[string]*Provider{
 		Version:        "v4.29.0",
 		RepositoryBase: "https://github.com/hashicorp",
 		RepositoryName: "terraform-provider-aws",
-		DocsBasePath:   "website/docs/",
+		DocsBasePaths:  []string{"website/docs/", "docs/"},
+		ResourceDocLayouts: []string{
+			"website/docs/r/%s.html.markdown",
+			"website/docs/r/%s.markdown",
+			"website/docs/r/%s.html.md",
+			"docs/resources/%s.md",
+			"website/docs/cdktf/typescript/r/%s.html.markdown",
+			"website/docs/cdktf/python/r/%s.html.markdown",
+		},
+		DataSourceDocLayouts: []string{
+			"website/docs/d/%s.html.markdown",
+			"website/docs/d/%s.markdown",
+			"website/docs/d/%s.html.md",
+			"docs/data-sources/%s.md",
+			"website/docs/cdktf/typescript/d/%s.html.markdown",
+			"website/docs/cdktf/python/d/%s.html.markdown",
+		},
 	},
 }
 
+// maxDocsExtractedSize caps the total size of the docs extracted from an
+// archive, guarding against zip bombs from mirrors; the AWS provider's docs
+// are tens of megabytes.
+const maxDocsExtractedSize = 1 << 30
+
 type Provider struct {
 	Name           string
 	Version        string
 	RepositoryBase string
 	RepositoryName string
-	DocsBasePath   string
-	rootDocPath string // e.g. .terrawrap/provider_docs/aws/v4.29.0
+	// DocsBasePaths are the directories of the repository holding docs; only
+	// these are extracted from archives.
+	DocsBasePaths []string
+	// ResourceDocLayouts and DataSourceDocLayouts are where docs may be found
+	// within the repository, tried in order; %s is the type name without the
+	// provider prefix (e.g. secretsmanager_secret).
+	ResourceDocLayouts   []string
+	DataSourceDocLayouts []string
+	// Checksums pins the SHA-256 of the repository archive per version.
+	Checksums       map[string]string
+	checksum        string
+	rootDocPath     string // e.g. .terrawrap/provider_docs/aws/v4.29.0
+	sourceDir       string // e.g. ~/src/terraform-provider-aws
+	downloadOptions DownloadOptions
 }
 
 func (p *Provider) SetRootDocPath(docPath string) {
 	p.rootDocPath = docPath
 }
 
-func (p *Provider) DocPath() string {
+func (p *Provider) SetDownloadOptions(opts DownloadOptions) {
+	p.downloadOptions = opts
+}
+
+// SetChecksum sets the SHA-256 the docs archive must have, overriding any
+// pinned in Checksums.
+func (p *Provider) SetChecksum(sum string) {
+	p.checksum = sum
+}
+
+// SetSourceDir reads docs straight from an existing checkout of the provider
+// repository, instead of the downloaded copy under the root doc path.
+func (p *Provider) SetSourceDir(dir string) {
+	p.sourceDir = dir
+}
+
+// SourceRoot is the root of the provider repository the docs are read from.
+func (p *Provider) SourceRoot() string {
+	if p.sourceDir != "" {
+		return p.sourceDir
+	}
+
+	return p.cacheSourceRoot(p.rootDocPath)
+}
+
+// cacheSourceRoot is where the provider repository is kept under a root doc
+// path, e.g. .terrawrap/provider_docs/aws/v4.29.0/terraform-provider-aws-4.29.0
+func (p *Provider) cacheSourceRoot(rootDocPath string) string {
 	// TODO: this is specific to AWS provider
 	re := regexp.MustCompile(`^v`)
-	return path.Join(p.rootDocPath, strings.Join([]string{p.RepositoryName, re.ReplaceAllString(p.Version, "")}, "-"), p.DocsBasePath)
+	return path.Join(rootDocPath, strings.Join([]string{p.RepositoryName, re.ReplaceAllString(p.Version, "")}, "-"))
+}
+
+// DocName strips the provider prefix from a resource or data source type,
+// matching the file names of its docs (e.g. aws_secretsmanager_secret's are
+// named secretsmanager_secret).
+func (p *Provider) DocName(typeName string) string {
+	return strings.TrimPrefix(typeName, p.Name+"_")
+}
+
+// ResourceDocPath finds the docs of a resource, trying each of the
+// provider's known layouts.
+func (p *Provider) ResourceDocPath(resourceType string) (string, error) {
+	return p.findDoc(resourceType, p.ResourceDocLayouts)
+}
+
+// DataSourceDocPath finds the docs of a data source, trying each of the
+// provider's known layouts.
+func (p *Provider) DataSourceDocPath(dataSourceType string) (string, error) {
+	return p.findDoc(dataSourceType, p.DataSourceDocLayouts)
+}
+
+func (p *Provider) findDoc(typeName string, layouts []string) (string, error) {
+	var tried []string
+
+	for _, layout := range layouts {
+		candidate := filepath.Join(p.SourceRoot(), fmt.Sprintf(layout, p.DocName(typeName)))
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+		tried = append(tried, candidate)
+	}
+
+	return "", fmt.Errorf("failed to find docs for %s, tried:\n  %s", typeName, strings.Join(tried, "\n  "))
+}
+
+// ImportDocsArchive populates the root doc path from a local zip of the
+// provider repository, e.g. a release archive downloaded elsewhere.
+func (p *Provider) ImportDocsArchive(zipFilePath string) error {
+	sum, err := p.verifyChecksum(zipFilePath)
+	if err != nil {
+		return err
+	}
+
+	return p.stageDocs(zipFilePath, sum, func(rootDocPath string) error {
+		if err := p.unzipDocFile(zipFilePath, rootDocPath); err != nil {
+			return fmt.Errorf("unable to unzip docs archive: %w", err)
+		}
+		return nil
+	})
+}
+
+// ImportDocsDir populates the root doc path from a local checkout of the
+// provider repository, copying only its docs.
+func (p *Provider) ImportDocsDir(dir string) error {
+	var docsBasePaths []string
+	for _, docsBasePath := range p.DocsBasePaths {
+		if _, err := os.Stat(filepath.Join(dir, docsBasePath)); err == nil {
+			docsBasePaths = append(docsBasePaths, docsBasePath)
+		}
+	}
+
+	if len(docsBasePaths) == 0 {
+		return fmt.Errorf("failed to find docs in %s under any of %s", dir, strings.Join(p.DocsBasePaths, ", "))
+	}
+
+	return p.stageDocs(dir, "", func(rootDocPath string) error {
+		copied := 0
+		for _, docsBasePath := range docsBasePaths {
+			n, err := copyDir(filepath.Join(dir, docsBasePath), filepath.Join(p.cacheSourceRoot(rootDocPath), docsBasePath))
+			if err != nil {
+				return err
+			}
+			copied += n
+		}
+
+		// Otherwise an empty cache would be marked complete
+		if copied == 0 {
+			return fmt.Errorf("found no docs files in %s under any of %s", dir, strings.Join(docsBasePaths, ", "))
+		}
+		return nil
+	})
+}
+
+// copyDir copies the tree at srcDocPath to dstDocPath, returning the number
+// of files copied.
+func copyDir(srcDocPath, dstDocPath string) (int, error) {
+	copied := 0
+	err := filepath.WalkDir(srcDocPath, func(src string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDocPath, src)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(dstDocPath, rel)
+
+		if d.IsDir() {
+			if err := os.MkdirAll(dst, 0754); err != nil {
+				return fmt.Errorf("failed to create directory %s: %w", dst, err)
+			}
+			return nil
+		}
+
+		if err := copyFile(src, dst); err != nil {
+			return fmt.Errorf("failed to copy %s to %s: %w", src, dst, err)
+		}
+		copied++
+
+		return nil
+	})
+
+	return copied, err
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
 }
 
 func (p *Provider) DownloadDocs() error {
-	fileName := p.Version + ".zip"
+	downloadURI, err := p.archiveURL()
+	if err != nil {
+		return err
+	}
 
-	tmpFilePath, err := p.downloadFile(fileName)
+	tmpFilePath, err := p.downloadFile(downloadURI)
 	if err != nil {
 		return fmt.Errorf("%w", err)
 	}
+	defer func() {
+		if err := p.cleanUpDownload(tmpFilePath); err != nil {
+			log.Printf("[WARN] while downloading provider docs: %s", err)
+		}
+	}()
+
+	sum, err := p.verifyChecksum(tmpFilePath)
+	if err != nil {
+		return err
+	}
+
+	return p.stageDocs(downloadURI, sum, func(rootDocPath string) error {
+		if err := p.unzipDocFile(tmpFilePath, rootDocPath); err != nil {
+			return fmt.Errorf("unable to unzip downloaded docs file: %w", err)
+		}
+		return nil
+	})
+}
+
+// verifyChecksum returns the SHA-256 of the archive, failing if it doesn't
+// match the one expected for this version.
+func (p *Provider) verifyChecksum(zipFilePath string) (string, error) {
+	f, err := os.Open(zipFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open docs archive %s: %w", zipFilePath, err)
+	}
+	defer f.Close()
 
-	if err := p.unzipDocFile(tmpFilePath); err != nil {
-		return fmt.Errorf("unable to unzip downloaded docs file: %w", err)
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to checksum docs archive %s: %w", zipFilePath, err)
 	}
+	sum := hex.EncodeToString(h.Sum(nil))
 
-	if err := p.cleanUpDownload(tmpFilePath); err != nil {
-		return fmt.Errorf("while downloading provider docs: %w", err)
+	expected := p.checksum
+	if expected == "" {
+		expected = p.Checksums[p.Version]
 	}
 
-	return err
+	if expected == "" {
+		log.Printf("[WARN] no SHA-256 is pinned for %s %s docs, so archive %s (SHA-256 %s) is unverified; pass --docs-sha256 to verify it", p.Name, p.Version, zipFilePath, sum)
+	} else if !strings.EqualFold(expected, sum) {
+		return sum, fmt.Errorf("docs archive %s has SHA-256 %s, expected %s", zipFilePath, sum, expected)
+	}
+
+	return sum, nil
 }
 
-func (p *Provider) unzipDocFile(zipFilePath string) error {
+func (p *Provider) unzipDocFile(zipFilePath, rootDocPath string) error {
 	var (
-		err error
-		zr  *zip.ReadCloser
-		out *os.File
-		fr  io.ReadCloser
+		err       error
+		zr        *zip.ReadCloser
+		extracted int64
+		written   int
 	)
 
 	zr, err = zip.OpenReader(zipFilePath)
@@ -77,44 +311,136 @@ func (p *Provider) unzipDocFile(zipFilePath string) error {
 	// TODO: checkerr
 	defer zr.Close()
 
+	// Archives of the repository hold everything under a single top level
+	// directory, named for wherever they came from; extract its contents to
+	// the expected source root regardless.
+	archiveRoot := p.zipArchiveRoot(zr.File)
+	sourceRoot := p.cacheSourceRoot(rootDocPath)
+
 	for _, f := range zr.File {
-		p := filepath.Join(p.rootDocPath, f.Name)
+		name := strings.TrimPrefix(f.Name, archiveRoot)
+
+		// Only the docs are ever read, and they're a fraction of the repository
+		if !p.isDocsPath(name) {
+			continue
+		}
+
+		p := filepath.Join(sourceRoot, filepath.FromSlash(name))
+		if !withinDir(sourceRoot, p) {
+			return fmt.Errorf("refusing to extract %s from %s: it escapes the destination directory", f.Name, zipFilePath)
+		}
 
 		if f.FileInfo().IsDir() {
-			if err := os.MkdirAll(p, os.ModePerm); err != nil {
+			if err := os.MkdirAll(p, 0754); err != nil {
 				return fmt.Errorf("failed to create directory %s: %w", p, err)
 			}
 			continue
 		}
 
-		if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
-			return fmt.Errorf("failed to create parent directories for file %s: %w", p, err)
+		// No symlinks, devices and the like; the docs are plain files
+		if !f.Mode().IsRegular() {
+			continue
 		}
 
-		out, err = os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-		if err != nil {
-			return fmt.Errorf("failed to create output file %s: %w", p, err)
+		if err := os.MkdirAll(filepath.Dir(p), 0754); err != nil {
+			return fmt.Errorf("failed to create parent directories for file %s: %w", p, err)
 		}
 
-		fr, err = f.Open()
+		n, err := extractZipFile(f, p, maxDocsExtractedSize-extracted)
 		if err != nil {
-			return fmt.Errorf("failed to open file in zip %s: %w", f.Name, err)
+			return err
 		}
+		extracted += n
+		written++
+	}
 
-		if _, err := io.Copy(out, fr); err != nil {
-			return fmt.Errorf("failed to copy file from zip (%s) to destination (%s): %w", f.Name, p, err)
+	// Otherwise an empty cache would be marked complete
+	if written == 0 {
+		return fmt.Errorf("found no docs in %s under any of %s", zipFilePath, strings.Join(p.DocsBasePaths, ", "))
+	}
+
+	return nil
+}
+
+// extractZipFile writes f to dst, failing if it's larger than limit bytes
+// once decompressed; archive headers aren't trusted for the size.
+func extractZipFile(f *zip.File, dst string, limit int64) (int64, error) {
+	fr, err := f.Open()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file in zip %s: %w", f.Name, err)
+	}
+	defer fr.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create output file %s: %w", dst, err)
+	}
+
+	n, err := io.CopyN(out, fr, limit+1)
+	if err != nil && err != io.EOF {
+		out.Close()
+		return n, fmt.Errorf("failed to copy file from zip (%s) to destination (%s): %w", f.Name, dst, err)
+	}
+
+	if err := out.Close(); err != nil {
+		return n, fmt.Errorf("encountered error while closing output file %s: %w", dst, err)
+	}
+
+	if n > limit {
+		return n, fmt.Errorf("refusing to extract %s: docs archive exceeds %d bytes uncompressed", f.Name, maxDocsExtractedSize)
+	}
+
+	return n, nil
+}
+
+// withinDir reports whether target is dir or is inside it.
+func withinDir(dir, target string) bool {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return false
+	}
+
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// isDocsPath reports whether the repository relative path is under one of
+// the docs base paths.
+func (p *Provider) isDocsPath(name string) bool {
+	for _, docsBasePath := range p.DocsBasePaths {
+		if strings.HasPrefix(name, path.Clean(docsBasePath)+"/") {
+			return true
 		}
+	}
 
-		if err := out.Close(); err != nil {
-			return fmt.Errorf("encountered error while closing output file %s: %w", p, err)
+	return false
+}
+
+// zipArchiveRoot returns the top level directory (with trailing slash) shared
+// by every file in the archive, if there is one. A directory the docs
+// themselves are under (e.g. website/, of an archive of just the docs) isn't
+// a root to strip.
+func (p *Provider) zipArchiveRoot(files []*zip.File) string {
+	var root string
+
+	for i, f := range files {
+		first, _, found := strings.Cut(f.Name, "/")
+		if !found {
+			return ""
 		}
+		if i == 0 {
+			root = first + "/"
+		} else if first+"/" != root {
+			return ""
+		}
+	}
 
-		if err := fr.Close(); err != nil {
-			return fmt.Errorf("encountered error while closing archive reader %s: %w", f.Name, err)
+	for _, docsBasePath := range p.DocsBasePaths {
+		if first, _, _ := strings.Cut(path.Clean(docsBasePath), "/"); first+"/" == root {
+			return ""
 		}
 	}
 
-	return err
+	return root
 }
 
 func (p *Provider) cleanUpDownload(fileName string) error {
@@ -125,60 +451,64 @@ func (p *Provider) cleanUpDownload(fileName string) error {
 	return nil
 }
 
-func (p *Provider) downloadFile(fileName string) (string, error) {
-	var (
-		downloadURI *url.URL
-		err         error
-		response    *http.Response
-		out         *os.File
-	)
-
-	targetFilePath := path.Join(os.TempDir(), fileName)
-	targetFileDir := filepath.Dir(targetFilePath)
-	if _, err := os.Stat(targetFileDir); os.IsNotExist(err) {
-		// create directory
-		if err := os.MkdirAll(targetFileDir, 0754); err != nil {
-			return targetFilePath, fmt.Errorf("failed to create target download directory %s: %w", targetFileDir, err)
-		}
+// archiveURL is where the provider repository archive for this version is
+// downloaded from, e.g.
+// https://github.com/hashicorp/terraform-provider-aws/archive/refs/tags/v4.29.0.zip
+func (p *Provider) archiveURL() (string, error) {
+	base := p.RepositoryBase
+	if p.downloadOptions.MirrorURL != "" {
+		base = p.downloadOptions.MirrorURL
 	}
 
-	// https://github.com/hashicorp/terraform-provider-aws/archive/refs/tags/v4.29.0.zip
 	// Hackish for now
-	downloadURI, err = url.Parse(p.RepositoryBase)
+	downloadURI, err := url.Parse(base)
 	if err != nil {
-		return targetFilePath, fmt.Errorf("failed to parse base repository uri %s for provider", p.RepositoryBase)
+		return "", fmt.Errorf("failed to parse base repository uri %s for provider", base)
 	}
-	downloadURI.Path = path.Join(downloadURI.Path, p.RepositoryName, "archive", "refs", "tags", fileName)
+	downloadURI.Path = path.Join(downloadURI.Path, p.RepositoryName, "archive", "refs", "tags", p.Version+".zip")
 
-	response, err = http.Get(downloadURI.String())
-	if err != nil {
-		return targetFilePath, fmt.Errorf("failed to fetch provider from %s: %w", downloadURI.String(), err)
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		return targetFilePath, fmt.Errorf("received status code %d while fetching docs", response.StatusCode)
-	}
+	return downloadURI.String(), nil
+}
 
-	out, err = os.Create(targetFilePath)
+// downloadFile fetches the uri to a new temporary file, returning its path.
+func (p *Provider) downloadFile(downloadURI string) (string, error) {
+	out, err := os.CreateTemp("", fmt.Sprintf("terrawrap-%s-%s-*.zip", p.Name, p.Version))
 	if err != nil {
-		return targetFilePath, fmt.Errorf("failed to create target docs file %s: %w", targetFilePath, err)
+		return "", fmt.Errorf("failed to create target docs file: %w", err)
 	}
 	defer out.Close()
 
-	_, err = io.Copy(out, response.Body)
-	if err != nil {
-		return targetFilePath, fmt.Errorf("failed to write http response to file: %w", err)
+	if err := p.downloadOptions.download(downloadURI, out); err != nil {
+		os.Remove(out.Name())
+		return "", err
 	}
 
-	return targetFilePath, nil
+	return out.Name(), nil
 }
 
-func GetProvider(name string) (*Provider, error) {
-	provider, ok := providerDefaults[name]
+// GetProvider returns the provider named, with docs pinned to the version
+// given; an empty version uses the provider's default.
+func GetProvider(name, version string) (*Provider, error) {
+	defaults, ok := providerDefaults[name]
 	if !ok {
 		return nil, fmt.Errorf("failed to locate support for provider %s", name)
 	}
 
-	return provider, nil
+	provider := *defaults
+	provider.downloadOptions = DefaultDownloadOptions()
+	if version != "" {
+		provider.Version = NormalizeVersion(version)
+	}
+
+	return &provider, nil
+}
+
+// NormalizeVersion prefixes version with "v", matching the providers' release
+// tags (e.g. 4.29.0 becomes v4.29.0).
+func NormalizeVersion(version string) string {
+	if version == "" || strings.HasPrefix(version, "v") {
+		return version
+	}
+
+	return "v" + version
 }
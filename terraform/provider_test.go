@@ -0,0 +1,231 @@
+package terraform
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type zipEntry struct {
+	name, content string
+}
+
+// writeZip writes an archive of the entries to a temporary file.
+func writeZip(t *testing.T, entries []zipEntry) string {
+	t.Helper()
+
+	zipPath := filepath.Join(t.TempDir(), "docs.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, entry := range entries {
+		w, err := zw.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return zipPath
+}
+
+func testProvider(t *testing.T) *Provider {
+	t.Helper()
+
+	p, err := GetProvider("aws", "v4.29.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetRootDocPath(filepath.Join(t.TempDir(), "aws", "v4.29.0"))
+
+	return p
+}
+
+func TestUnzipDocFileArchiveRoot(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		want    string
+	}{
+		{
+			name: "repository archive",
+			entries: []zipEntry{
+				{"terraform-provider-aws-4.29.0/README.md", "readme"},
+				{"terraform-provider-aws-4.29.0/website/docs/r/secretsmanager_secret.html.markdown", "docs"},
+			},
+			want: "website/docs/r/secretsmanager_secret.html.markdown",
+		},
+		{
+			name: "mirror named root",
+			entries: []zipEntry{
+				{"mirror-abc123/website/docs/r/secretsmanager_secret.html.markdown", "docs"},
+			},
+			want: "website/docs/r/secretsmanager_secret.html.markdown",
+		},
+		{
+			name: "docs only archive",
+			entries: []zipEntry{
+				{"website/docs/r/secretsmanager_secret.html.markdown", "docs"},
+				{"website/docs/d/secretsmanager_secret.html.markdown", "docs"},
+			},
+			want: "website/docs/r/secretsmanager_secret.html.markdown",
+		},
+		{
+			name: "registry docs only archive",
+			entries: []zipEntry{
+				{"docs/resources/secretsmanager_secret.md", "docs"},
+			},
+			want: "docs/resources/secretsmanager_secret.md",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testProvider(t)
+			staging := t.TempDir()
+
+			if err := p.unzipDocFile(writeZip(t, tt.entries), staging); err != nil {
+				t.Fatalf("unzipDocFile() error = %v", err)
+			}
+
+			want := filepath.Join(p.cacheSourceRoot(staging), filepath.FromSlash(tt.want))
+			if _, err := os.Stat(want); err != nil {
+				t.Errorf("expected %s to be extracted: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestUnzipDocFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []zipEntry
+		wantErr string
+	}{
+		{
+			name:    "empty archive",
+			entries: []zipEntry{},
+			wantErr: "found no docs",
+		},
+		{
+			name: "no docs",
+			entries: []zipEntry{
+				{"terraform-provider-aws-4.29.0/README.md", "readme"},
+				{"terraform-provider-aws-4.29.0/main.go", "package main"},
+			},
+			wantErr: "found no docs",
+		},
+		{
+			name: "zip slip",
+			entries: []zipEntry{
+				{"website/docs/r/secretsmanager_secret.html.markdown", "docs"},
+				{"website/docs/../../../../escaped.md", "escaped"},
+			},
+			wantErr: "escapes the destination directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testProvider(t)
+			staging := t.TempDir()
+
+			err := p.unzipDocFile(writeZip(t, tt.entries), staging)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("unzipDocFile() error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExtractZipFileLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   int64
+		wantErr bool
+	}{
+		{name: "under the limit", limit: 100},
+		{name: "at the limit", limit: 10},
+		{name: "over the limit", limit: 9, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zr, err := zip.OpenReader(writeZip(t, []zipEntry{{"docs.md", "0123456789"}}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer zr.Close()
+
+			_, err = extractZipFile(zr.File[0], filepath.Join(t.TempDir(), "docs.md"), tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("extractZipFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportDocsDirEmpty(t *testing.T) {
+	p := testProvider(t)
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "website", "docs", "r"), 0754); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.ImportDocsDir(dir); err == nil {
+		t.Fatal("ImportDocsDir() succeeded without any docs files")
+	}
+	if p.DocCacheComplete() {
+		t.Error("ImportDocsDir() marked an empty doc cache complete")
+	}
+}
+
+func TestImportDocsArchiveChecksum(t *testing.T) {
+	archive := writeZip(t, []zipEntry{{"website/docs/r/secretsmanager_secret.html.markdown", "docs"}})
+
+	b, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(b)
+
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  bool
+	}{
+		{name: "unpinned"},
+		{name: "matching", checksum: hex.EncodeToString(sum[:])},
+		{name: "matching upper case", checksum: strings.ToUpper(hex.EncodeToString(sum[:]))},
+		{name: "mismatched", checksum: strings.Repeat("0", 64), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testProvider(t)
+			p.SetChecksum(tt.checksum)
+
+			err := p.ImportDocsArchive(archive)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ImportDocsArchive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got, want := p.DocCacheComplete(), !tt.wantErr; got != want {
+				t.Errorf("DocCacheComplete() = %v, want %v", got, want)
+			}
+		})
+	}
+}
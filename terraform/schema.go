@@ -0,0 +1,214 @@
+package terraform
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/infracasts/terraform-provider-aws-expose-internal/provider"
+)
+
+// SchemaSource supplies the resource and data source schemas of a provider.
+type SchemaSource interface {
+	ProviderSchema(ctx context.Context) (*ProviderSchema, error)
+}
+
+// embeddedProviderVersion is the hashicorp/terraform-provider-aws release the
+// provider compiled into terrawrap was forked from.
+const embeddedProviderVersion = "v4.29.0"
+
+// ProviderSchema holds the schemas of every resource and data source
+// shipped by a provider, keyed by type name (e.g. aws_secretsmanager_secret).
+type ProviderSchema struct {
+	// Version is the provider release the schemas belong to, if known.
+	Version     string
+	Resources   map[string]*schema.Resource
+	DataSources map[string]*schema.Resource
+}
+
+// schemaCache memoizes the result of loading a provider schema, as doing so
+// means initializing (or launching) the whole provider.
+type schemaCache struct {
+	once   sync.Once
+	schema *ProviderSchema
+	err    error
+}
+
+func (c *schemaCache) load(fn func() (*ProviderSchema, error)) (*ProviderSchema, error) {
+	c.once.Do(func() {
+		c.schema, c.err = fn()
+	})
+
+	return c.schema, c.err
+}
+
+// EmbeddedSchemaSource reads schemas from the AWS provider compiled into
+// terrawrap. The provider muxes SDKv2 and terraform-plugin-framework
+// resources, so the muxed server's schema response is used to find every
+// resource, and SDKv2 schemas are preferred where they exist as they carry
+// more detail (defaults, ForceNew, validation) than the protocol does.
+type EmbeddedSchemaSource struct {
+	cache schemaCache
+}
+
+func NewEmbeddedSchemaSource() *EmbeddedSchemaSource {
+	return &EmbeddedSchemaSource{}
+}
+
+func (s *EmbeddedSchemaSource) ProviderSchema(ctx context.Context) (*ProviderSchema, error) {
+	return s.cache.load(func() (*ProviderSchema, error) {
+		hcProvider, err := provider.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize hashicorp terraform provider: %w", err)
+		}
+
+		serverFactory, err := provider.ProtoV5ProviderServerFactory(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize muxed hashicorp terraform provider: %w", err)
+		}
+
+		resp, err := serverFactory().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch schema from muxed hashicorp terraform provider: %w", err)
+		}
+
+		ps, err := providerSchemaFromProto(resp)
+		if err != nil {
+			return nil, err
+		}
+
+		ps.Version = embeddedProviderVersion
+
+		for name, r := range hcProvider.ResourcesMap {
+			ps.Resources[name] = r
+		}
+
+		for name, r := range hcProvider.DataSourcesMap {
+			ps.DataSources[name] = r
+		}
+
+		return ps, nil
+	})
+}
+
+// providerSchemaFromProto converts a protocol v5 schema response into the
+// SDKv2 schema model the rest of terrawrap works with.
+func providerSchemaFromProto(resp *tfprotov5.GetProviderSchemaResponse) (*ProviderSchema, error) {
+	for _, diag := range resp.Diagnostics {
+		if diag.Severity == tfprotov5.DiagnosticSeverityError {
+			return nil, fmt.Errorf("provider returned an error fetching its schema: %s: %s", diag.Summary, diag.Detail)
+		}
+	}
+
+	ps := &ProviderSchema{
+		Resources:   make(map[string]*schema.Resource, len(resp.ResourceSchemas)),
+		DataSources: make(map[string]*schema.Resource, len(resp.DataSourceSchemas)),
+	}
+
+	for name, s := range resp.ResourceSchemas {
+		ps.Resources[name] = resourceFromProto(s)
+	}
+
+	for name, s := range resp.DataSourceSchemas {
+		ps.DataSources[name] = resourceFromProto(s)
+	}
+
+	return ps, nil
+}
+
+func resourceFromProto(s *tfprotov5.Schema) *schema.Resource {
+	r := resourceFromBlock(s.Block)
+	r.SchemaVersion = int(s.Version)
+
+	// The SDK adds "id" to every resource on its own, and it's never part of
+	// ResourcesMap; keep both sources consistent.
+	delete(r.Schema, "id")
+
+	return r
+}
+
+func resourceFromBlock(block *tfprotov5.SchemaBlock) *schema.Resource {
+	r := &schema.Resource{Schema: make(map[string]*schema.Schema)}
+	if block == nil {
+		return r
+	}
+
+	r.Description = block.Description
+	if block.Deprecated {
+		r.DeprecationMessage = "deprecated"
+	}
+
+	for _, attr := range block.Attributes {
+		s := schemaFromType(attr.Type)
+		s.Description = attr.Description
+		s.Required = attr.Required
+		s.Optional = attr.Optional
+		s.Computed = attr.Computed
+		s.Sensitive = attr.Sensitive
+		if attr.Deprecated {
+			s.Deprecated = "deprecated"
+		}
+		r.Schema[attr.Name] = s
+	}
+
+	for _, nested := range block.BlockTypes {
+		s := &schema.Schema{
+			Elem:     resourceFromBlock(nested.Block),
+			MinItems: int(nested.MinItems),
+			MaxItems: int(nested.MaxItems),
+		}
+
+		switch nested.Nesting {
+		case tfprotov5.SchemaNestedBlockNestingModeSet:
+			s.Type = schema.TypeSet
+		case tfprotov5.SchemaNestedBlockNestingModeMap:
+			s.Type = schema.TypeMap
+		case tfprotov5.SchemaNestedBlockNestingModeSingle, tfprotov5.SchemaNestedBlockNestingModeGroup:
+			s.Type = schema.TypeList
+			s.MaxItems = 1
+		default:
+			s.Type = schema.TypeList
+		}
+
+		if nested.MinItems > 0 {
+			s.Required = true
+		} else {
+			s.Optional = true
+		}
+		r.Schema[nested.TypeName] = s
+	}
+
+	return r
+}
+
+// schemaFromType maps a terraform type onto the closest SDKv2 schema type.
+// Object types have no SDKv2 attribute equivalent, so they're represented as
+// a single-item list of a nested resource, as the SDK does for blocks.
+func schemaFromType(t tftypes.Type) *schema.Schema {
+	switch {
+	case t.Is(tftypes.Bool):
+		return &schema.Schema{Type: schema.TypeBool}
+	case t.Is(tftypes.Number):
+		return &schema.Schema{Type: schema.TypeFloat}
+	case t.Is(tftypes.List{}):
+		return &schema.Schema{Type: schema.TypeList, Elem: schemaFromType(t.(tftypes.List).ElementType)}
+	case t.Is(tftypes.Set{}):
+		return &schema.Schema{Type: schema.TypeSet, Elem: schemaFromType(t.(tftypes.Set).ElementType)}
+	case t.Is(tftypes.Map{}):
+		return &schema.Schema{Type: schema.TypeMap, Elem: schemaFromType(t.(tftypes.Map).ElementType)}
+	case t.Is(tftypes.Object{}):
+		elem := &schema.Resource{Schema: make(map[string]*schema.Schema)}
+		for name, attrType := range t.(tftypes.Object).AttributeTypes {
+			attr := schemaFromType(attrType)
+			attr.Optional = true
+			elem.Schema[name] = attr
+		}
+		return &schema.Schema{Type: schema.TypeList, MaxItems: 1, Elem: elem}
+	default:
+		// strings, and anything dynamic
+		return &schema.Schema{Type: schema.TypeString}
+	}
+}
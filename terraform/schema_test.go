@@ -0,0 +1,164 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSchemaFromType(t *testing.T) {
+	tests := []struct {
+		name string
+		t    tftypes.Type
+		want *schema.Schema
+	}{
+		{name: "string", t: tftypes.String, want: &schema.Schema{Type: schema.TypeString}},
+		{name: "number", t: tftypes.Number, want: &schema.Schema{Type: schema.TypeFloat}},
+		{name: "bool", t: tftypes.Bool, want: &schema.Schema{Type: schema.TypeBool}},
+		{name: "dynamic", t: tftypes.DynamicPseudoType, want: &schema.Schema{Type: schema.TypeString}},
+		{
+			name: "list",
+			t:    tftypes.List{ElementType: tftypes.String},
+			want: &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}},
+		},
+		{
+			name: "set",
+			t:    tftypes.Set{ElementType: tftypes.Number},
+			want: &schema.Schema{Type: schema.TypeSet, Elem: &schema.Schema{Type: schema.TypeFloat}},
+		},
+		{
+			name: "map",
+			t:    tftypes.Map{ElementType: tftypes.Bool},
+			want: &schema.Schema{Type: schema.TypeMap, Elem: &schema.Schema{Type: schema.TypeBool}},
+		},
+		{
+			name: "list of lists",
+			t:    tftypes.List{ElementType: tftypes.List{ElementType: tftypes.String}},
+			want: &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeString}}},
+		},
+		{
+			name: "object",
+			t:    tftypes.Object{AttributeTypes: map[string]tftypes.Type{"region": tftypes.String, "days": tftypes.Number}},
+			want: &schema.Schema{Type: schema.TypeList, MaxItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"region": {Type: schema.TypeString, Optional: true},
+				"days":   {Type: schema.TypeFloat, Optional: true},
+			}}},
+		},
+		{
+			name: "list of objects",
+			t:    tftypes.List{ElementType: tftypes.Object{AttributeTypes: map[string]tftypes.Type{"region": tftypes.String}}},
+			want: &schema.Schema{Type: schema.TypeList, Elem: &schema.Schema{Type: schema.TypeList, MaxItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"region": {Type: schema.TypeString, Optional: true},
+			}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schemaFromType(tt.t); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("schemaFromType() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceFromBlock(t *testing.T) {
+	tests := []struct {
+		name  string
+		block *tfprotov5.SchemaBlock
+		want  *schema.Resource
+	}{
+		{
+			name: "no block",
+			want: &schema.Resource{Schema: map[string]*schema.Schema{}},
+		},
+		{
+			name: "attributes",
+			block: &tfprotov5.SchemaBlock{
+				Description: "A secret.",
+				Attributes: []*tfprotov5.SchemaAttribute{
+					{Name: "name", Type: tftypes.String, Required: true, Description: "Name of the secret."},
+					{Name: "kms_key_id", Type: tftypes.String, Optional: true, Deprecated: true},
+					{Name: "arn", Type: tftypes.String, Computed: true},
+					{Name: "tags_all", Type: tftypes.Map{ElementType: tftypes.String}, Optional: true, Computed: true},
+					{Name: "secret_string", Type: tftypes.String, Optional: true, Sensitive: true},
+				},
+			},
+			want: &schema.Resource{
+				Description: "A secret.",
+				Schema: map[string]*schema.Schema{
+					"name":          {Type: schema.TypeString, Required: true, Description: "Name of the secret."},
+					"kms_key_id":    {Type: schema.TypeString, Optional: true, Deprecated: "deprecated"},
+					"arn":           {Type: schema.TypeString, Computed: true},
+					"tags_all":      {Type: schema.TypeMap, Optional: true, Computed: true, Elem: &schema.Schema{Type: schema.TypeString}},
+					"secret_string": {Type: schema.TypeString, Optional: true, Sensitive: true},
+				},
+			},
+		},
+		{
+			name: "nested blocks",
+			block: &tfprotov5.SchemaBlock{
+				Deprecated: true,
+				BlockTypes: []*tfprotov5.SchemaNestedBlock{
+					{
+						TypeName: "replica",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeSet,
+						Block: &tfprotov5.SchemaBlock{Attributes: []*tfprotov5.SchemaAttribute{
+							{Name: "region", Type: tftypes.String, Required: true},
+						}},
+					},
+					{
+						TypeName: "rotation_rules",
+						Nesting:  tfprotov5.SchemaNestedBlockNestingModeList,
+						MinItems: 1,
+						MaxItems: 1,
+					},
+					{TypeName: "timeouts", Nesting: tfprotov5.SchemaNestedBlockNestingModeSingle},
+					{TypeName: "settings", Nesting: tfprotov5.SchemaNestedBlockNestingModeMap},
+				},
+			},
+			want: &schema.Resource{
+				DeprecationMessage: "deprecated",
+				Schema: map[string]*schema.Schema{
+					"replica": {Type: schema.TypeSet, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"region": {Type: schema.TypeString, Required: true},
+					}}},
+					"rotation_rules": {Type: schema.TypeList, Required: true, MinItems: 1, MaxItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{}}},
+					"timeouts":       {Type: schema.TypeList, Optional: true, MaxItems: 1, Elem: &schema.Resource{Schema: map[string]*schema.Schema{}}},
+					"settings":       {Type: schema.TypeMap, Optional: true, Elem: &schema.Resource{Schema: map[string]*schema.Schema{}}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resourceFromBlock(tt.block); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resourceFromBlock() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceFromProto(t *testing.T) {
+	r := resourceFromProto(&tfprotov5.Schema{
+		Version: 2,
+		Block: &tfprotov5.SchemaBlock{Attributes: []*tfprotov5.SchemaAttribute{
+			{Name: "id", Type: tftypes.String, Optional: true, Computed: true},
+			{Name: "name", Type: tftypes.String, Required: true},
+		}},
+	})
+
+	if r.SchemaVersion != 2 {
+		t.Errorf("SchemaVersion = %d, want 2", r.SchemaVersion)
+	}
+	if _, ok := r.Schema["id"]; ok {
+		t.Error("resourceFromProto() kept id, which the SDK never has in its schema")
+	}
+	if _, ok := r.Schema["name"]; !ok {
+		t.Error("resourceFromProto() dropped name")
+	}
+}
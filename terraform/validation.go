@@ -0,0 +1,335 @@
+package terraform
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	hclcty "github.com/zclconf/go-cty/cty"
+)
+
+// Constraint restricts the values of an argument, as described by its docs:
+// one of Values, or between Min and Max (inclusive) when they're set.
+type Constraint struct {
+	Values []string `json:"values,omitempty" yaml:"values,omitempty"`
+	Min    *float64 `json:"min,omitempty" yaml:"min,omitempty"`
+	Max    *float64 `json:"max,omitempty" yaml:"max,omitempty"`
+}
+
+var (
+	// e.g. "Valid values are `A`, `B`" or "Valid values: `A` or `B`."
+	constraintValuesFormat = regexp.MustCompile("(?i)(?:valid|possible|allowed) values(?:\\s+(?:are|include))?\\s*:?\\s*((?:`[^`]+`(?:,?\\s*(?:and|or)?\\s*)?)+)")
+	// e.g. "This value can be `0` to force deletion without recovery"
+	constraintCanBeFormat = regexp.MustCompile("(?i)can be ((?:`[^`]+`(?:,?\\s*(?:or)?\\s*)?)+)")
+	// e.g. "between 7 and 30" or "range from `7` to `30`"
+	constraintRangeFormat = regexp.MustCompile("(?i)(?:between|range from|ranges from|from)\\s+`?(-?[0-9.]+)`?\\s+(?:and|to)\\s+`?(-?[0-9.]+)`?")
+	constraintCodeSpan    = regexp.MustCompile("`([^`]+)`")
+)
+
+// ParseConstraint extracts the values or range an argument's raw markdown
+// description says it accepts, if any.
+func ParseConstraint(description string) *Constraint {
+	var c Constraint
+
+	if subMatches := constraintValuesFormat.FindStringSubmatch(description); subMatches != nil {
+		c.Values = codeSpans(subMatches[1])
+	}
+
+	if subMatches := constraintRangeFormat.FindStringSubmatch(description); subMatches != nil {
+		min, minErr := strconv.ParseFloat(subMatches[1], 64)
+		max, maxErr := strconv.ParseFloat(subMatches[2], 64)
+		if minErr == nil && maxErr == nil && min <= max {
+			c.Min, c.Max = &min, &max
+
+			// values allowed outside the range, e.g. "can be `0` ... or
+			// range from `7` to `30`"
+			if c.Values == nil {
+				if subMatches := constraintCanBeFormat.FindStringSubmatch(description); subMatches != nil {
+					c.Values = codeSpans(subMatches[1])
+				}
+			}
+		}
+	}
+
+	if c.Values == nil && c.Min == nil {
+		return nil
+	}
+
+	return &c
+}
+
+func codeSpans(s string) []string {
+	var spans []string
+	for _, subMatches := range constraintCodeSpan.FindAllStringSubmatch(s, -1) {
+		spans = append(spans, subMatches[1])
+	}
+	return spans
+}
+
+// CheckConstraint reconciles the constraint the docs describe with the
+// schema: it's dropped for types it can't apply to, and where the schema's
+// validation disagrees with it, the schema wins and the mismatch is
+// reported. Without one from the docs, integers get whatever values or range
+// probing their validation finds.
+func (r *TFResource) CheckConstraint(entry IOEntry, c *Constraint) *Constraint {
+	if entry.Schema == nil {
+		return nil
+	}
+
+	s := entry.Schema
+	elem := s
+	if s.Type == schema.TypeList || s.Type == schema.TypeSet {
+		var ok bool
+		if elem, ok = s.Elem.(*schema.Schema); !ok {
+			return nil
+		}
+	}
+
+	if c == nil {
+		return probeConstraint(elem, entry.Name)
+	}
+
+	switch elem.Type {
+	case schema.TypeString:
+		// ranges of strings are lengths, which docs rarely mean
+		c.Min, c.Max = nil, nil
+	case schema.TypeInt, schema.TypeFloat:
+		for _, value := range c.Values {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				r.diagnoseEntry(DiagnosticMismatch, entry, "docs of %s.%s give the non-numeric valid value %q, so its validation was omitted.", r.Type, entry.Path(), value)
+				return nil
+			}
+		}
+	default:
+		return nil
+	}
+
+	if elem.ValidateFunc == nil && elem.ValidateDiagFunc == nil {
+		if len(c.Values) == 0 && c.Min == nil {
+			return nil
+		}
+		return c
+	}
+
+	// Probe the schema's validation with the values the docs describe
+	var rejected []string
+	for _, value := range c.Values {
+		if !validates(elem, entry.Name, value) {
+			rejected = append(rejected, value)
+		}
+	}
+	if len(rejected) > 0 {
+		r.diagnoseEntry(DiagnosticMismatch, entry, "docs of %s.%s give %s as valid, but the schema rejects them, so its validation was omitted.", r.Type, entry.Path(), strings.Join(rejected, ", "))
+		return nil
+	}
+
+	if c.Min != nil {
+		min, max := formatNumber(*c.Min), formatNumber(*c.Max)
+		if !validates(elem, entry.Name, min) || !validates(elem, entry.Name, max) {
+			r.diagnoseEntry(DiagnosticMismatch, entry, "docs of %s.%s give the range %s to %s, but the schema rejects its bounds, so its validation was omitted.", r.Type, entry.Path(), min, max)
+			return nil
+		}
+
+		below, above := formatNumber(*c.Min-1), formatNumber(*c.Max+1)
+		for _, outside := range []string{below, above} {
+			if validates(elem, entry.Name, outside) && !contains(c.Values, outside) {
+				r.diagnoseEntry(DiagnosticMismatch, entry, "docs of %s.%s give the range %s to %s, but the schema accepts %s, so its validation was omitted.", r.Type, entry.Path(), min, max, outside)
+				return nil
+			}
+		}
+	}
+
+	return c
+}
+
+const (
+	// probeWindow is how far either side of zero every integer is probed,
+	// finding values allowed one by one (e.g. of validation.IntInSlice)
+	probeWindow = 1024
+	// probeLimit is as far as a range (e.g. of validation.IntBetween) is
+	// followed beyond the window; further than that, it's unbounded
+	probeLimit = math.MaxInt32
+	// probeMaxValues is the most values a constraint is made of; more
+	// than that, and they're not a useful list
+	probeMaxValues = 20
+)
+
+// probeConstraint finds the values or range an integer's validation accepts,
+// by trying every integer near zero, then following a range found to its
+// ends. Validation accepting everything, or a range without both ends, gives
+// no constraint. Strings can't be probed, as there's no telling what to try.
+func probeConstraint(s *schema.Schema, key string) *Constraint {
+	if s.Type != schema.TypeInt || (s.ValidateFunc == nil && s.ValidateDiagFunc == nil) {
+		return nil
+	}
+
+	accepts := func(i int) bool {
+		return validates(s, key, strconv.Itoa(i))
+	}
+
+	var accepted []int
+	for i := -probeWindow; i <= probeWindow; i++ {
+		if accepts(i) {
+			accepted = append(accepted, i)
+		}
+	}
+	if len(accepted) == 0 {
+		return nil
+	}
+
+	min, max := accepted[0], accepted[len(accepted)-1]
+	contiguous := max-min+1 == len(accepted)
+
+	// a range continuing past the window
+	if contiguous && min == -probeWindow {
+		if min = rangeEnd(accepts, min, -probeLimit); min == -probeLimit {
+			return nil
+		}
+	}
+	if contiguous && max == probeWindow {
+		if max = rangeEnd(accepts, max, probeLimit); max == probeLimit {
+			return nil
+		}
+	}
+
+	if contiguous && min != max {
+		fmin, fmax := float64(min), float64(max)
+		return &Constraint{Min: &fmin, Max: &fmax}
+	}
+
+	if len(accepted) > probeMaxValues || accepted[0] == -probeWindow || accepted[len(accepted)-1] == probeWindow {
+		return nil
+	}
+
+	c := &Constraint{}
+	for _, i := range accepted {
+		c.Values = append(c.Values, strconv.Itoa(i))
+	}
+	return c
+}
+
+// rangeEnd binary searches from an accepted value towards limit for the last
+// value accepted, assuming everything between them is.
+func rangeEnd(accepts func(int) bool, from, limit int) int {
+	if accepts(limit) {
+		return limit
+	}
+
+	// from is accepted, limit isn't
+	for from-limit > 1 || limit-from > 1 {
+		mid := from + (limit-from)/2
+		if accepts(mid) {
+			from = mid
+		} else {
+			limit = mid
+		}
+	}
+
+	return from
+}
+
+// validates reports whether the schema's validation accepts a value, given
+// as it's written in the docs.
+func validates(s *schema.Schema, key, value string) (ok bool) {
+	var v interface{} = value
+	switch s.Type {
+	case schema.TypeInt:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return false
+		}
+		v = i
+	case schema.TypeFloat:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		v = f
+	}
+
+	// validation funcs assume they're given the schema's type, and may panic
+	// otherwise
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	if s.ValidateDiagFunc != nil {
+		if diags := s.ValidateDiagFunc(v, cty.GetAttrPath(key)); diags.HasError() {
+			return false
+		}
+	}
+
+	if s.ValidateFunc != nil {
+		if _, errs := s.ValidateFunc(v, key); len(errs) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Condition is the HCL condition checking a value against the constraint.
+func (c Constraint) Condition(s *schema.Schema, value string) string {
+	var checks []string
+
+	if len(c.Values) > 0 {
+		literals := make([]string, 0, len(c.Values))
+		for _, v := range c.Values {
+			if isNumeric(s) {
+				literals = append(literals, v)
+			} else {
+				// quoted as HCL, escaping what Terraform would interpolate
+				literals = append(literals, string(hclwrite.TokensForValue(hclcty.StringVal(v)).Bytes()))
+			}
+		}
+		checks = append(checks, fmt.Sprintf("contains([%s], %s)", strings.Join(literals, ", "), value))
+	}
+
+	if c.Min != nil {
+		checks = append(checks, fmt.Sprintf("(%s >= %s && %s <= %s)", value, formatNumber(*c.Min), value, formatNumber(*c.Max)))
+	}
+
+	return strings.Join(checks, " || ")
+}
+
+// ErrorMessage describes the values the constraint accepts, as a sentence.
+func (c Constraint) ErrorMessage(name string) string {
+	var accepted []string
+
+	if len(c.Values) > 0 {
+		accepted = append(accepted, "one of "+strings.Join(c.Values, ", "))
+	}
+
+	if c.Min != nil {
+		accepted = append(accepted, fmt.Sprintf("between %s and %s", formatNumber(*c.Min), formatNumber(*c.Max)))
+	}
+
+	return fmt.Sprintf("The %s must be %s.", name, strings.Join(accepted, ", or "))
+}
+
+func isNumeric(s *schema.Schema) bool {
+	if elem, ok := s.Elem.(*schema.Schema); ok && (s.Type == schema.TypeList || s.Type == schema.TypeSet) {
+		s = elem
+	}
+	return s.Type == schema.TypeInt || s.Type == schema.TypeFloat
+}
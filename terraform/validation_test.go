@@ -0,0 +1,167 @@
+package terraform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        *Constraint
+	}{
+		{
+			name:        "none",
+			description: "Description of the secret.",
+		},
+		{
+			name:        "valid values are",
+			description: "Type of the key. Valid values are `AES_256`, `AES_128` and `RSA_2048`.",
+			want:        &Constraint{Values: []string{"AES_256", "AES_128", "RSA_2048"}},
+		},
+		{
+			name:        "valid values with a colon",
+			description: "Protocol to use. Valid values: `TCP` or `UDP`.",
+			want:        &Constraint{Values: []string{"TCP", "UDP"}},
+		},
+		{
+			name:        "possible values",
+			description: "Possible values include `ENABLED`, `DISABLED`.",
+			want:        &Constraint{Values: []string{"ENABLED", "DISABLED"}},
+		},
+		{
+			name:        "between",
+			description: "Number of days. Must be between 7 and 30.",
+			want:        &Constraint{Min: float(7), Max: float(30)},
+		},
+		{
+			name:        "range in code spans",
+			description: "Number of days, which can range from `7` to `30`.",
+			want:        &Constraint{Min: float(7), Max: float(30)},
+		},
+		{
+			name:        "range with values outside it",
+			description: "This value can be `0` to force deletion without recovery or range from `7` to `30` days. The default value is `30`.",
+			want:        &Constraint{Values: []string{"0"}, Min: float(7), Max: float(30)},
+		},
+		{
+			name:        "negative range",
+			description: "Offset between -12 and 14.",
+			want:        &Constraint{Min: float(-12), Max: float(14)},
+		},
+		{
+			name:        "inverted range",
+			description: "Anything from 30 to 7.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseConstraint(tt.description); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConstraint() = %s, want %s", formatConstraint(got), formatConstraint(tt.want))
+			}
+		})
+	}
+}
+
+func formatConstraint(c *Constraint) string {
+	if c == nil {
+		return "nil"
+	}
+	return c.ErrorMessage("value")
+}
+
+func TestCheckConstraint(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *schema.Schema
+		docs   *Constraint
+		want   *Constraint
+	}{
+		{
+			name:   "docs without validation",
+			schema: &schema.Schema{Type: schema.TypeString, Optional: true},
+			docs:   &Constraint{Values: []string{"A", "B"}},
+			want:   &Constraint{Values: []string{"A", "B"}},
+		},
+		{
+			name:   "docs agreeing with validation",
+			schema: &schema.Schema{Type: schema.TypeString, Optional: true, ValidateFunc: validation.StringInSlice([]string{"A", "B"}, false)},
+			docs:   &Constraint{Values: []string{"A", "B"}},
+			want:   &Constraint{Values: []string{"A", "B"}},
+		},
+		{
+			name:   "docs disagreeing with validation",
+			schema: &schema.Schema{Type: schema.TypeString, Optional: true, ValidateFunc: validation.StringInSlice([]string{"A"}, false)},
+			docs:   &Constraint{Values: []string{"A", "B"}},
+		},
+		{
+			name:   "docs range narrower than validation",
+			schema: &schema.Schema{Type: schema.TypeInt, Optional: true, ValidateFunc: validation.IntBetween(0, 30)},
+			docs:   &Constraint{Min: float(7), Max: float(30)},
+		},
+		{
+			name:   "docs range with values outside it",
+			schema: &schema.Schema{Type: schema.TypeInt, Optional: true, ValidateFunc: validation.Any(validation.IntInSlice([]int{0}), validation.IntBetween(7, 30))},
+			docs:   &Constraint{Values: []string{"0"}, Min: float(7), Max: float(30)},
+			want:   &Constraint{Values: []string{"0"}, Min: float(7), Max: float(30)},
+		},
+		{
+			name:   "string range",
+			schema: &schema.Schema{Type: schema.TypeString, Optional: true},
+			docs:   &Constraint{Min: float(1), Max: float(64)},
+		},
+		{
+			name:   "probed range",
+			schema: &schema.Schema{Type: schema.TypeInt, Optional: true, ValidateFunc: validation.IntBetween(7, 30)},
+			want:   &Constraint{Min: float(7), Max: float(30)},
+		},
+		{
+			name:   "probed range past the window",
+			schema: &schema.Schema{Type: schema.TypeInt, Optional: true, ValidateFunc: validation.IntBetween(60, 86400)},
+			want:   &Constraint{Min: float(60), Max: float(86400)},
+		},
+		{
+			name:   "probed values",
+			schema: &schema.Schema{Type: schema.TypeInt, Optional: true, ValidateDiagFunc: validation.ToDiagFunc(validation.IntInSlice([]int{1, 7, 30}))},
+			want:   &Constraint{Values: []string{"1", "7", "30"}},
+		},
+		{
+			name:   "probed list of integers",
+			schema: &schema.Schema{Type: schema.TypeList, Optional: true, Elem: &schema.Schema{Type: schema.TypeInt, ValidateFunc: validation.IntBetween(1, 5)}},
+			want:   &Constraint{Min: float(1), Max: float(5)},
+		},
+		{
+			name:   "probed range without an end",
+			schema: &schema.Schema{Type: schema.TypeInt, Optional: true, ValidateFunc: validation.IntAtLeast(1)},
+		},
+		{
+			name:   "probed without validation",
+			schema: &schema.Schema{Type: schema.TypeInt, Optional: true},
+		},
+		{
+			name:   "probed string",
+			schema: &schema.Schema{Type: schema.TypeString, Optional: true, ValidateFunc: validation.StringInSlice([]string{"A", "B"}, false)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := testResource(t)
+			entry := IOEntry{Schema: tt.schema, Resource: r, Name: "value"}
+
+			got := r.CheckConstraint(entry, tt.docs)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckConstraint() = %s, want %s", formatConstraint(got), formatConstraint(tt.want))
+			}
+		})
+	}
+}
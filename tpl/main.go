@@ -47,6 +47,10 @@ func TFStringFormatter(str string) string {
 			searchSpace := Min(len(str)-1, pos+lineLimit)
 
 			nextSpace := strings.Index(str[searchSpace:], " ")
+			if nextSpace < 0 {
+				// no more spaces; don't break the last word
+				nextSpace = len(str) - searchSpace
+			}
 			pos = searchSpace + nextSpace
 			if pos >= len(str) {
 				newStr = newStr + str[startPos:]
@@ -62,14 +66,18 @@ func TFStringFormatter(str string) string {
 
 		return fmt.Sprintf(`<<EOF
 %s
-EOF`, newStr)
-	} else if strings.Contains(str, `"`) {
-		return fmt.Sprintf("`%s`", str)
+EOF`, tfTemplateEscaper.Replace(newStr))
 	} else {
-		return fmt.Sprintf(`"%s"`, str)
+		return fmt.Sprintf(`"%s"`, tfStringEscaper.Replace(str))
 	}
 }
 
+var (
+	// tfTemplateEscaper escapes the sequences terraform would interpolate
+	tfTemplateEscaper = strings.NewReplacer("${", "$${", "%{", "%%{")
+	tfStringEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", "$${", "%{", "%%{")
+)
+
 func ResourceTemplate() []byte {
 	return []byte(`/*
 {{ .TerrawrapLine }}
@@ -81,7 +89,13 @@ func ResourceTemplate() []byte {
 
 resource "{{ .Type }}" "{{ .Name }}" {
 {{- $max_argument_len := .MaxArgumentLength }}
-{{- range $index, $elem := .Arguments }}
+{{- range $groupIndex, $group := .ArgumentGroups }}
+  {{- if $group.Title }}
+    {{- if $groupIndex }}
+{{ end }}
+    // {{ $group.Title }}
+  {{- end }}
+{{- range $index, $elem := $group.Arguments }}
   {{- with $elem }}
     {{- if .Deprecated }}
     // {{ .Name }} = var.{{ .PrefixedName }} // DEPRECATED
@@ -90,6 +104,7 @@ resource "{{ .Type }}" "{{ .Name }}" {
     {{- end -}}
   {{- end -}}
 {{- end }}
+{{- end }}
 }
 `)
 }
@@ -103,13 +118,27 @@ func VariablesTemplate() []byte {
 {{ if .Legal.Header }}{{ .Legal.Header }}{{ end -}}
 */
 
-{{ range $index, $elem := .Arguments }}
+{{ range $group := .ArgumentGroups }}
+{{- if $group.Title }}
+// -----------------------------------------------------------------------------
+// {{ $group.Title }}
+// -----------------------------------------------------------------------------
+{{ end }}
+{{- range $index, $elem := $group.Arguments }}
 variable "{{ $elem.PrefixedName }}" {
   type = {{ $elem.ValueType }}
-  {{ if $elem.DefaultValue }}default = {{ $elem.DefaultValue }}{{ end }}
-  description = {{ $elem.Description | tfStringFormat }}
+  {{ if $elem.DefaultValue }}default = {{ $elem.DefaultValue }}{{ else if not $elem.Required }}default = null{{ end }}
+  description = {{ $elem.VariableDescription | tfStringFormat }}
+  {{- if $elem.Constraint }}
+
+  validation {
+    condition     = {{ $elem.ValidationCondition }}
+    error_message = {{ $elem.ValidationMessage | tfStringFormat }}
+  }
+  {{- end }}
 }
 {{ end }}
+{{- end }}
 `)
 }
 
@@ -129,7 +158,46 @@ func OutputTemplate() []byte {
 output "{{ $elem.PrefixedName }}" {
   value = {{ $resource_type }}.{{ $resource_name }}.{{ $elem.Name }}
   description = {{ $elem.Description | tfStringFormat }}
+  {{- if $elem.IsSensitive }}
+  sensitive = true
+  {{- end }}
+}
+{{ end -}}
+`)
 }
+
+func ExampleTemplate() []byte {
+	return []byte(`/*
+{{ .TerrawrapLine }}
+
+{{ .Copyright }}
+
+{{ if .Legal.Header }}{{ .Legal.Header }}{{ end -}}
+*/
+
+{{ .HCL -}}
+`)
+}
+
+func ReadmeTemplate() []byte {
+	return []byte(`# {{ .Type }}.{{ .Name }}
+
+## Importing existing resources
+
+To bring an existing ` + "`{{ .Type }}`" + ` under this module's management, call the module
+from a root configuration with an import block, as ` + "`examples/import/main.tf`" + ` does: set
+` + "`{{ .ImportIDVariable }}`" + ` to its ID, and the next apply imports it as
+` + "`module.{{ .Name }}.{{ .Type }}.{{ .Name }}`" + `. Terraform only allows import blocks in a root
+module, so the module itself has none; taking their ID from a variable needs Terraform
+1.6 or later.
+{{ if .Import.Description }}
+From the provider's documentation:
+
+> {{ .Import.Description }}
+{{- if .Import.Example }}
+>
+> ` + "`{{ .Import.Example }}`" + `
+{{- end }}
 {{ end -}}
 `)
 }